@@ -1,45 +1,70 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
 	"os"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/config"
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/metrics"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/middleware"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/migrations"
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/repository"
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/router"
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/service"
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/usecase"
 	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/auth"
 	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/database"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/events"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/health"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/lifecycle"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/tracing"
 )
 
 func main() {
-	dbConfig := database.Config{
-		Host:     "localhost", // или ваш хост базы данных
-		Port:     "5432",      // стандартный порт PostgreSQL
-		User:     "postgres",  // ваш пользователь базы данных
-		Password: "postgres",  // ваш пароль
-		DBName:   "forum",     // имя вашей базы данных
-		SSLMode:  "disable",   // режим SSL
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
 	}
-	db, err := database.ConnectWithConfig(dbConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := database.ConnectWithConfig(database.Config{
+		Host:     cfg.DB.Host,
+		Port:     cfg.DB.Port,
+		User:     cfg.DB.User,
+		Password: cfg.DB.Password,
+		DBName:   cfg.DB.Name,
+		SSLMode:  cfg.DB.SSLMode,
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer db.Close()
 
 	// Проверка соединения с базой данных
 	if err := db.Ping(); err != nil {
 		log.Fatalf("Failed to ping database: %v", err)
 	}
 
-	if err := runMigrations(db); err != nil {
-		log.Fatalf("Migrations failed: %v", err)
+	if len(args) > 0 {
+		runCommand(db, args)
+		db.Close()
+		return
+	}
+
+	if cfg.Migrations.Auto {
+		if err := migrations.Up(db); err != nil {
+			log.Fatalf("Migrations failed: %v", err)
+		}
+		log.Println("Migrations applied successfully")
 	}
 
 	// Проверка соединения
@@ -50,64 +75,117 @@ func main() {
 	// Инициализация репозитория
 	userRepo := repository.NewSQLUserRepository(db)
 	tokenRepo := repository.NewSQLTokenRepository(db)
+	sanctionRepo := repository.NewSQLSanctionRepository(db)
+	outboxRepo := repository.NewSQLOutboxRepository(db)
+	txManager := repository.NewSQLTxManager(db)
 
 	// Инициализация менеджера токенов
 	tokenManager := auth.NewTokenManager(
-		os.Getenv("ACCESS_TOKEN_SECRET"),
-		os.Getenv("REFRESH_TOKEN_SECRET"),
-		24*time.Hour,  // Access token expiry
-		720*time.Hour, // Refresh token expiry (30 дней)
+		cfg.Auth.AccessSecret,
+		cfg.Auth.RefreshSecret,
+		cfg.Auth.AccessTTL,
+		cfg.Auth.RefreshTTL,
 	)
 
 	// Инициализация usecase
 	authUsecase := usecase.NewAuthUsecase(
 		userRepo,
 		tokenRepo, // Добавляем tokenRepo
+		sanctionRepo,
+		outboxRepo,
+		txManager,
 		tokenManager,
+		cfg.Auth.BcryptCost,
 	)
+	sanctionUsecase := usecase.NewSanctionUsecase(userRepo, tokenRepo, sanctionRepo, outboxRepo, txManager)
+	syncUsecase := usecase.NewSyncUsecase(userRepo, sanctionRepo, outboxRepo)
 
 	// Инициализация сервиса
 	authService := service.NewAuthService(authUsecase)
+	sanctionService := service.NewSanctionService(sanctionUsecase)
+	syncService := service.NewSyncService(syncUsecase)
+
+	// База закрывается последней, после серверов, фоновых задач и шины
+	logger := log.New(os.Stdout, "[AUTH] ", log.LstdFlags)
+	app := lifecycle.New(logger, cfg.HTTP.DrainDelay, cfg.HTTP.ShutdownTimeout)
+	app.OnClose("database", db.Close)
+	metrics.RegisterDB(db, "auth")
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "auth-service",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.OTLPEndpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	// Спаны отправляются после остановки запросов и задач, но до закрытия базы
+	app.OnClose("tracing", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return shutdownTracing(ctx)
+	})
+
+	// События из outbox уходят в NATS: без него relay отмечал бы их отправленными впустую
+	bus, err := events.NewNATSBus(cfg.Events.NATSURL, "auth-service", cfg.Events.SubjectPrefix, logger)
+	if err != nil {
+		log.Fatalf("Failed to connect to NATS: %v", err)
+	}
+	app.OnClose("event bus", bus.Close)
+
+	outboxRelay := service.NewOutboxRelay(outboxRepo, bus, logger)
+	app.Go("outbox relay", func(ctx context.Context) {
+		outboxRelay.Run(ctx, cfg.Events.OutboxInterval)
+	})
 
 	// Инициализация контроллера
 	authController := controller.NewAuthController(authService)
+	adminController := controller.NewAdminController(sanctionService)
+	syncController := controller.NewSyncController(syncService)
+
+	// Пробы готовности: трафик снимают только база и версия схемы. Очередь outbox
+	// лишь отмечается в отчете - вход и регистрация работают и без шины.
+	probes := health.New(app.Ready, cfg.Health.CacheTTL, cfg.Health.CheckTimeout)
+	probes.Add("postgres", health.Ping(db))
+	probes.Add("migrations", func(ctx context.Context) error { return migrations.Verify(ctx, db) })
+	probes.AddOptional("outbox", outboxRelay.CheckBacklog(int64(cfg.Health.OutboxMaxPending)))
 
 	// Настройка роутера
-	r := router.SetupRouter(authController)
+	adminMiddleware := middleware.RequireRole(tokenManager, userRepo, sanctionRepo, entity.RoleAdmin, entity.RoleModerator)
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit.PerMinute, cfg.RateLimit.Burst)
+
+	r := router.SetupRouter(
+		authController,
+		adminController,
+		adminMiddleware,
+		syncController,
+		middleware.RequireServiceToken(cfg.Events.SyncToken),
+		rateLimiter.ByIP(),
+		cfg.CORS.AllowedOrigins,
+		probes,
+	)
 
-	// Определение порта
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8081"
-	}
+	port := cfg.HTTP.Port
 
 	// Запуск сервера
 	log.Printf("Auth Service is running on port %s", port)
-	if err := r.Run(":" + port); err != nil {
+	app.Serve(&http.Server{Addr: ":" + port, Handler: r})
+	if err := app.Run(); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+}
 
-} // Исправленная функция runMigrations
-func runMigrations(db *sql.DB) error {
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	if err != nil {
-		return err
+// runCommand выполняет подкоманду вместо запуска сервера: auth-service migrate up
+func runCommand(db *sql.DB, args []string) {
+	if args[0] != "migrate" {
+		log.Fatalf("Unknown command %q, expected migrate", args[0])
 	}
-
-	// Правильный путь к миграциям
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://C:/Users/usr09/OneDrive/Рабочий%20стол/fooorum/backend/auth-service/internal/migrations", // Исправленный путь
-		"postgres",
-		driver,
-	)
+	m, err := migrations.New(db)
 	if err != nil {
-		return err
+		log.Fatalf("Failed to prepare migrations: %v", err)
 	}
-
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return err
+	if err := migrations.Command(m, args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
 	}
-
-	log.Println("Migrations applied successfully")
-	return nil
 }
@@ -0,0 +1,194 @@
+// Package config собирает настройки auth-service из значений по умолчанию,
+// файла, переменных окружения и флагов и проверяет их до запуска
+package config
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Config struct {
+	HTTP       HTTP       `key:"http"`
+	DB         DB         `key:"db"`
+	Auth       Auth       `key:"auth"`
+	CORS       CORS       `key:"cors"`
+	RateLimit  RateLimit  `key:"rate_limit"`
+	Events     Events     `key:"events"`
+	Migrations Migrations `key:"migrations"`
+	Health     Health     `key:"health"`
+	Tracing    Tracing    `key:"tracing"`
+}
+
+type HTTP struct {
+	Port            string        `key:"port" env:"PORT" desc:"HTTP port"`
+	DrainDelay      time.Duration `key:"drain_delay" env:"DRAIN_DELAY" desc:"how long readiness reports false before the server stops accepting connections"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" desc:"on shutdown, deadline for in-flight requests and then, separately, for background jobs"`
+}
+
+type DB struct {
+	Host     string `key:"host" env:"DB_HOST" desc:"Postgres host"`
+	Port     string `key:"port" env:"DB_PORT" desc:"Postgres port"`
+	User     string `key:"user" env:"DB_USER" desc:"Postgres user"`
+	Password string `key:"password" env:"DB_PASSWORD" desc:"Postgres password"`
+	Name     string `key:"name" env:"DB_NAME" desc:"Postgres database"`
+	SSLMode  string `key:"sslmode" env:"DB_SSLMODE" desc:"Postgres sslmode"`
+}
+
+type Auth struct {
+	AccessSecret  string        `key:"access_secret" env:"ACCESS_TOKEN_SECRET" desc:"access token signing key"`
+	RefreshSecret string        `key:"refresh_secret" env:"REFRESH_TOKEN_SECRET" desc:"refresh token signing key"`
+	AccessTTL     time.Duration `key:"access_ttl" env:"ACCESS_TOKEN_TTL" desc:"access token lifetime"`
+	RefreshTTL    time.Duration `key:"refresh_ttl" env:"REFRESH_TOKEN_TTL" desc:"refresh token lifetime"`
+	BcryptCost    int           `key:"bcrypt_cost" env:"BCRYPT_COST" desc:"bcrypt cost for password hashes"`
+}
+
+type CORS struct {
+	AllowedOrigins []string `key:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" desc:"comma-separated allowed origins"`
+}
+
+// RateLimit ограничивает вход, регистрацию и обновление токенов с одного IP
+type RateLimit struct {
+	PerMinute int `key:"per_minute" env:"RATE_LIMIT_PER_MINUTE" desc:"auth requests per minute per IP, 0 disables"`
+	Burst     int `key:"burst" env:"RATE_LIMIT_BURST" desc:"auth requests allowed in a burst"`
+}
+
+type Events struct {
+	NATSURL        string        `key:"nats_url" env:"NATS_URL" desc:"NATS server URL, required to deliver events to forum-service"`
+	SubjectPrefix  string        `key:"subject_prefix" env:"EVENTS_SUBJECT_PREFIX" desc:"prefix for event subjects"`
+	OutboxInterval time.Duration `key:"outbox_interval" env:"OUTBOX_INTERVAL" desc:"how often the outbox is relayed"`
+	// SyncToken - общий секрет, с которым forum-service забирает снимки пользователей
+	SyncToken string `key:"sync_token" env:"EVENTS_SYNC_TOKEN" desc:"shared secret for the /internal user snapshot API"`
+}
+
+type Migrations struct {
+	Auto bool `key:"auto" env:"MIGRATE_ON_START" desc:"apply pending migrations on startup"`
+}
+
+// Health - пробы /readyz; результат кэшируется, чтобы частые пробы не нагружали базу
+type Health struct {
+	CacheTTL         time.Duration `key:"cache_ttl" env:"HEALTH_CACHE_TTL" desc:"how long a readiness report is reused"`
+	CheckTimeout     time.Duration `key:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" desc:"timeout of a single dependency check"`
+	OutboxMaxPending int           `key:"outbox_max_pending" env:"HEALTH_OUTBOX_MAX_PENDING" desc:"unpublished outbox events above which readiness reports degraded"`
+}
+
+// Tracing - экспорт спанов OpenTelemetry. Заголовки traceparent передаются
+// дальше при любом экспортере.
+type Tracing struct {
+	Exporter     string  `key:"exporter" env:"TRACING_EXPORTER" desc:"span exporter: none, stdout or otlp"`
+	OTLPEndpoint string  `key:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" desc:"OTLP/HTTP collector URL"`
+	SampleRatio  float64 `key:"sample_ratio" env:"TRACING_SAMPLE_RATIO" desc:"share of new traces that are recorded, from 0 to 1"`
+}
+
+// minSecretLength - короче ключ HMAC подбирается слишком легко
+const minSecretLength = 16
+
+func defaults() *Config {
+	return &Config{
+		HTTP: HTTP{Port: "8081", DrainDelay: 5 * time.Second, ShutdownTimeout: 30 * time.Second},
+		DB: DB{
+			Host:     "localhost",
+			Port:     "5432",
+			User:     "postgres",
+			Password: "postgres",
+			Name:     "forum",
+			SSLMode:  "disable",
+		},
+		Auth: Auth{
+			AccessTTL:  24 * time.Hour,
+			RefreshTTL: 720 * time.Hour,
+			BcryptCost: bcrypt.DefaultCost,
+		},
+		CORS:       CORS{AllowedOrigins: []string{"http://localhost:3000"}},
+		RateLimit:  RateLimit{PerMinute: 20, Burst: 10},
+		Events:     Events{OutboxInterval: time.Second},
+		Migrations: Migrations{Auto: true},
+		Health:     Health{CacheTTL: 2 * time.Second, CheckTimeout: time.Second, OutboxMaxPending: 1000},
+		Tracing:    Tracing{Exporter: "none", OTLPEndpoint: "http://localhost:4318", SampleRatio: 1},
+	}
+}
+
+// Load читает конфигурацию; args - аргументы командной строки без имени программы.
+// Вторым значением возвращает аргументы после флагов, например "migrate up".
+// При ошибках возвращает Problems со всеми найденными нарушениями.
+func Load(args []string) (*Config, []string, error) {
+	cfg := defaults()
+	rest, err := loadValidated(cfg, "auth-service", args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, rest, nil
+}
+
+func (c *Config) Validate() error {
+	var p Problems
+	p.check(validPort(c.HTTP.Port), "http.port: %q is not a valid port", c.HTTP.Port)
+	p.check(c.HTTP.DrainDelay >= 0, "http.drain_delay must not be negative")
+	p.check(c.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+	p.check(c.DB.Host != "", "db.host is required")
+	p.check(validPort(c.DB.Port), "db.port: %q is not a valid port", c.DB.Port)
+	p.check(c.DB.User != "", "db.user is required")
+	p.check(c.DB.Name != "", "db.name is required")
+
+	p.check(len(c.Auth.AccessSecret) >= minSecretLength,
+		"auth.access_secret (ACCESS_TOKEN_SECRET) must be at least %d characters", minSecretLength)
+	p.check(len(c.Auth.RefreshSecret) >= minSecretLength,
+		"auth.refresh_secret (REFRESH_TOKEN_SECRET) must be at least %d characters", minSecretLength)
+	p.check(c.Auth.AccessSecret == "" || c.Auth.AccessSecret != c.Auth.RefreshSecret,
+		"auth.access_secret and auth.refresh_secret must differ")
+	p.check(c.Auth.AccessTTL > 0, "auth.access_ttl must be positive")
+	p.check(c.Auth.RefreshTTL > c.Auth.AccessTTL, "auth.refresh_ttl must be longer than auth.access_ttl")
+	p.check(c.Auth.BcryptCost >= bcrypt.MinCost && c.Auth.BcryptCost <= bcrypt.MaxCost,
+		"auth.bcrypt_cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+
+	p.check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins must list at least one origin")
+	for _, origin := range c.CORS.AllowedOrigins {
+		p.check(validOrigin(origin), "cors.allowed_origins: %q is not an origin like https://example.com", origin)
+	}
+
+	p.check(c.RateLimit.PerMinute >= 0, "rate_limit.per_minute must not be negative")
+	p.check(c.RateLimit.PerMinute == 0 || c.RateLimit.Burst > 0, "rate_limit.burst must be positive")
+
+	// Без шины relay отметил бы события отправленными, хотя их никто не получил
+	if c.Events.NATSURL == "" {
+		p.check(false, "events.nats_url (NATS_URL) is required: forum-service learns about users only through events")
+	} else {
+		u, err := url.Parse(c.Events.NATSURL)
+		p.check(err == nil && u.Scheme == "nats" && u.Host != "", "events.nats_url: %q is not a nats:// URL", c.Events.NATSURL)
+	}
+	p.check(c.Events.OutboxInterval > 0, "events.outbox_interval must be positive")
+	p.check(len(c.Events.SyncToken) >= minSecretLength,
+		"events.sync_token (EVENTS_SYNC_TOKEN) must be at least %d characters", minSecretLength)
+
+	p.check(c.Health.CacheTTL >= 0, "health.cache_ttl must not be negative")
+	p.check(c.Health.CheckTimeout > 0, "health.check_timeout must be positive")
+	p.check(c.Health.OutboxMaxPending > 0, "health.outbox_max_pending must be positive")
+	c.Tracing.validate(&p)
+	return p.err()
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+// validOrigin - "*" не допускается: CORS настроен с передачей учетных данных
+func validOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == ""
+}
+
+func (t Tracing) validate(p *Problems) {
+	switch t.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		u, err := url.Parse(t.OTLPEndpoint)
+		p.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"tracing.otlp_endpoint: %q is not an absolute http(s) URL", t.OTLPEndpoint)
+	default:
+		p.check(false, "tracing.exporter: %q is not one of none, stdout, otlp", t.Exporter)
+	}
+	p.check(t.SampleRatio >= 0 && t.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Problems - все ошибки конфигурации разом, чтобы не исправлять их по одной за запуск
+type Problems []string
+
+func (p Problems) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(p, "\n  - ")
+}
+
+func (p *Problems) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		*p = append(*p, fmt.Sprintf(format, args...))
+	}
+}
+
+func (p Problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return p
+}
+
+type validator interface {
+	Validate() error
+}
+
+// loadValidated - load и Validate с общим отчетом: ошибки разбора и проверки показываются вместе
+func loadValidated(cfg validator, name string, args []string) ([]string, error) {
+	var problems Problems
+	rest, err := load(cfg, name, args)
+	if err != nil && !errors.As(err, &problems) {
+		return nil, err
+	}
+	var invalid Problems
+	if err := cfg.Validate(); errors.As(err, &invalid) {
+		problems = append(problems, invalid...)
+	}
+	return rest, problems.err()
+}
+
+// setting - одна настройка: ключ в файле (раздел.имя), переменная окружения и флаг
+type setting struct {
+	key   string
+	env   string
+	flag  string
+	desc  string
+	value reflect.Value
+}
+
+// load заполняет cfg, в котором уже стоят значения по умолчанию. Источники по
+// возрастанию приоритета: файл (-config или CONFIG_FILE), переменные окружения, флаги.
+// Описание настроек берется из тегов key, env и desc полей cfg.
+// Возвращает аргументы, оставшиеся после флагов (подкоманду).
+func load(cfg interface{}, name string, args []string) ([]string, error) {
+	settings := collect(reflect.ValueOf(cfg).Elem(), "")
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	flags := make(map[string]string)
+	for _, s := range settings {
+		s := s
+		fs.Func(s.flag, fmt.Sprintf("%s (env %s)", s.desc, s.env), func(raw string) error {
+			flags[s.key] = raw
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var problems Problems
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("config file %s: %v", path, err))
+		}
+		byKey := make(map[string]*setting, len(settings))
+		for _, s := range settings {
+			byKey[s.key] = s
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s, ok := byKey[key]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("config file %s: unknown key %q", path, key))
+				continue
+			}
+			if err := set(s.value, values[key]); err != nil {
+				problems = append(problems, fmt.Sprintf("config file %s: %s: %v", path, key, err))
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if raw := os.Getenv(s.env); raw != "" {
+			if err := set(s.value, raw); err != nil {
+				problems = append(problems, fmt.Sprintf("env %s: %v", s.env, err))
+			}
+		}
+	}
+	for _, s := range settings {
+		if raw, ok := flags[s.key]; ok {
+			if err := set(s.value, raw); err != nil {
+				problems = append(problems, fmt.Sprintf("flag -%s: %v", s.flag, err))
+			}
+		}
+	}
+	return fs.Args(), problems.err()
+}
+
+func collect(v reflect.Value, prefix string) []*setting {
+	var settings []*setting
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("key")
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if f.Type.Kind() == reflect.Struct {
+			settings = append(settings, collect(v.Field(i), key)...)
+			continue
+		}
+		settings = append(settings, &setting{
+			key:   key,
+			env:   f.Tag.Get("env"),
+			flag:  strings.NewReplacer(".", "-", "_", "-").Replace(key),
+			desc:  f.Tag.Get("desc"),
+			value: v.Field(i),
+		})
+	}
+	return settings
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func set(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// readFile читает файл конфигурации в плоский набор "раздел.имя" -> значение
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("unsupported format, use .yaml, .yml or .toml")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	flatten(tree, "", values)
+	return values, nil
+}
+
+func flatten(tree map[string]interface{}, prefix string, values map[string]string) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(v, key, values)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/service"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/usecase"
+)
+
+type AdminController struct {
+	sanctions service.SanctionService
+}
+
+func NewAdminController(s service.SanctionService) *AdminController {
+	return &AdminController{sanctions: s}
+}
+
+func (c *AdminController) ApplySanction(ctx *gin.Context) {
+	userID, ok := parseIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req usecase.ApplySanctionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sanction, err := c.sanctions.Apply(ctx.Request.Context(), ctx.GetUint("userID"), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrValidation):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, sanction)
+}
+
+func (c *AdminController) ListSanctions(ctx *gin.Context) {
+	userID, ok := parseIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	sanctions, err := c.sanctions.ListByUser(ctx.Request.Context(), userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, sanctions)
+}
+
+func (c *AdminController) LiftSanction(ctx *gin.Context) {
+	sanctionID, ok := parseIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.sanctions.Lift(ctx.Request.Context(), ctx.GetUint("userID"), sanctionID); err != nil {
+		if errors.Is(err, usecase.ErrSanctionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func parseIDParam(ctx *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/service"
@@ -23,7 +24,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	resp, err := c.service.Register(req)
+	resp, err := c.service.Register(ctx.Request.Context(), req)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -37,9 +38,9 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	resp, err := c.service.Login(req)
+	resp, err := c.service.Login(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		ctx.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, resp)
@@ -52,10 +53,19 @@ func (c *AuthController) Refresh(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	resp, err := c.service.Refresh(req)
+	resp, err := c.service.Refresh(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		ctx.JSON(authErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, resp)
 }
+
+// authErrorStatus отличает заблокированный аккаунт (403) от неверных учётных данных (401)
+func authErrorStatus(err error) int {
+	var sanctionErr *usecase.SanctionError
+	if errors.As(err, &sanctionErr) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/service"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/usecase"
+)
+
+const (
+	defaultSnapshotPage = 100
+	maxSnapshotPage     = 500
+)
+
+// SyncController отдает снимки пользователей forum-service: по ним он восстанавливает
+// пользователей и санкции, события о которых не получил
+type SyncController struct {
+	sync service.SyncService
+}
+
+func NewSyncController(s service.SyncService) *SyncController {
+	return &SyncController{sync: s}
+}
+
+func (c *SyncController) GetUser(ctx *gin.Context) {
+	userID, ok := parseIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	snapshot, err := c.sync.Snapshot(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, snapshot)
+}
+
+// ListUsers отдает страницу снимков по возрастанию ID; следующая страница
+// запрашивается с after, равным последнему полученному ID
+func (c *SyncController) ListUsers(ctx *gin.Context) {
+	after, err := strconv.ParseUint(ctx.DefaultQuery("after", "0"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid after"})
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultSnapshotPage)))
+	if err != nil || limit <= 0 || limit > maxSnapshotPage {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxSnapshotPage)})
+		return
+	}
+
+	snapshots, err := c.sync.Snapshots(ctx.Request.Context(), uint(after), limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, snapshots)
+}
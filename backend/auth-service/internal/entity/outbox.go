@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// Типы событий, которые auth-service публикует через outbox
+const (
+	EventUserRegistered = "user.registered"
+	EventUserUpdated    = "user.updated"
+	EventUserBanned     = "user.banned" // бан или временная блокировка
+	EventUserMuted      = "user.muted"
+	EventSanctionLifted = "user.sanction_lifted"
+)
+
+const AggregateUser = "user"
+
+// OutboxEvent - событие, записанное в той же транзакции, что и изменение,
+// и ожидающее отправки в шину
+type OutboxEvent struct {
+	ID            int64
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       []byte
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+	Attempts      int
+	LastError     string
+	// TraceContext - traceparent/tracestate запроса, породившего событие
+	TraceContext map[string]string
+}
+
+// UserEventPayload - состояние пользователя после изменения
+type UserEventPayload struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+// UserSnapshot - текущее состояние пользователя и всех его санкций. По нему
+// потребитель восстанавливает то, что пропустил в событиях. Version - номер
+// последнего события пользователя в outbox на момент снимка.
+type UserSnapshot struct {
+	User      UserEventPayload       `json:"user"`
+	Sanctions []SanctionEventPayload `json:"sanctions"`
+	Version   int64                  `json:"version"`
+}
+
+// SanctionEventPayload - санкция после применения или снятия
+type SanctionEventPayload struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Type      string     `json:"type"`
+	Reason    string     `json:"reason"`
+	StartsAt  time.Time  `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	LiftedAt  *time.Time `json:"lifted_at,omitempty"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Типы санкций против пользователя
+const (
+	SanctionSuspension = "suspension" // временная блокировка аккаунта
+	SanctionBan        = "ban"        // бессрочная блокировка аккаунта
+	SanctionMute       = "mute"       // запрет на публикацию, вход разрешён
+)
+
+type Sanction struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Type      string     `json:"type"`
+	Reason    string     `json:"reason"`
+	IssuedBy  *uint      `json:"issued_by,omitempty"`
+	StartsAt  time.Time  `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	LiftedAt  *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy  *uint      `json:"lifted_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsActive сообщает, действует ли санкция в момент now
+func (s *Sanction) IsActive(now time.Time) bool {
+	if s.LiftedAt != nil || now.Before(s.StartsAt) {
+		return false
+	}
+	return s.ExpiresAt == nil || now.Before(*s.ExpiresAt)
+}
+
+// BlocksLogin сообщает, запрещает ли санкция вход в аккаунт
+func (s *Sanction) BlocksLogin() bool {
+	return s.Type == SanctionBan || s.Type == SanctionSuspension
+}
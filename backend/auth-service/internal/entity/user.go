@@ -1,9 +1,27 @@
 package entity
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// RoleRank упорядочивает роли: санкции можно выдавать только тем, у кого ранг ниже
+func RoleRank(role string) int {
+	switch role {
+	case RoleAdmin:
+		return 2
+	case RoleModerator:
+		return 1
+	}
+	return 0
+}
+
 type User struct {
 	ID           uint   `json:"id"`
 	Username     string `json:"username"`
 	Email        string `json:"email"`
+	Role         string `json:"role"`
 	Password     string `json:"-"`
 	RefreshToken string `json:"-"`
 }
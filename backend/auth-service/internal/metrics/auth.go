@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+)
+
+var (
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Login attempts by result.",
+	}, []string{"result"})
+
+	Registrations = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "auth_registrations_total",
+		Help: "Registered users.",
+	})
+
+	Refreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_token_refreshes_total",
+		Help: "Refresh token exchanges by result.",
+	}, []string{"result"})
+)
+
+// Result - значение метки result для исхода операции
+func Result(err error) string {
+	if err != nil {
+		return ResultFailed
+	}
+	return ResultSucceeded
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/auth"
+)
+
+// RequireRole пропускает только пользователей с одной из указанных ролей.
+// Роль и санкции читаются из базы: забаненный модератор теряет доступ сразу,
+// а не когда истечет его access-токен.
+func RequireRole(
+	tokenManager auth.TokenManager,
+	userRepo repository.UserRepository,
+	sanctionRepo repository.SanctionRepository,
+	roles ...string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if tokenString == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
+			return
+		}
+
+		userID, err := tokenManager.ParseAccessToken(tokenString)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		user, err := userRepo.FindByID(c.Request.Context(), userID)
+		if err != nil || user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			return
+		}
+
+		sanctions, err := sanctionRepo.FindActive(c.Request.Context(), user.ID, time.Now())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check account status"})
+			return
+		}
+		for _, s := range sanctions {
+			if s.BlocksLogin() {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "account " + s.Type})
+				return
+			}
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Set("userID", user.ID)
+				c.Set("userRole", user.Role)
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	}
+}
+
+// RequireServiceToken пропускает запросы других сервисов с общим секретом в заголовке
+// Authorization: Bearer <token>
+func RequireServiceToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid service token"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_sanctions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    reason TEXT NOT NULL,
+    issued_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    starts_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP,
+    lifted_at TIMESTAMP,
+    lifted_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sanctions_user_id ON user_sanctions(user_id);
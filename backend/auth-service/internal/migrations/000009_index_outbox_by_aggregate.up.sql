@@ -0,0 +1,2 @@
+-- Версия снимка пользователя - последний номер его события в outbox
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate ON outbox(aggregate_type, aggregate_id, id);
@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const Usage = `usage: migrate <command>
+  up         apply all pending migrations
+  down [N]   roll back N migrations (default 1)
+  goto V     migrate up or down to version V
+  version    print the current version
+  force V    set version V without running migrations, clearing the dirty flag`
+
+// Command выполняет подкоманду migrate и пишет результат в out
+func Command(m *migrate.Migrate, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+
+	var err error
+	switch cmd, rest := args[0], args[1:]; cmd {
+	case "up":
+		if len(rest) != 0 {
+			return errors.New(Usage)
+		}
+		err = m.Up()
+	case "down":
+		steps := 1
+		if len(rest) > 1 {
+			return errors.New(Usage)
+		}
+		if len(rest) == 1 {
+			if steps, err = strconv.Atoi(rest[0]); err != nil || steps < 1 {
+				return fmt.Errorf("down: %q is not a positive number of steps", rest[0])
+			}
+		}
+		err = m.Steps(-steps)
+	case "goto":
+		if len(rest) != 1 {
+			return errors.New(Usage)
+		}
+		version, perr := strconv.ParseUint(rest[0], 10, 32)
+		if perr != nil {
+			return fmt.Errorf("goto: %q is not a version", rest[0])
+		}
+		err = m.Migrate(uint(version))
+	case "force":
+		if len(rest) != 1 {
+			return errors.New(Usage)
+		}
+		version, perr := strconv.Atoi(rest[0])
+		if perr != nil || version < -1 {
+			return fmt.Errorf("force: %q is not a version", rest[0])
+		}
+		err = m.Force(version)
+	case "version":
+		if len(rest) != 0 {
+			return errors.New(Usage)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", cmd, Usage)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Fprintln(out, "no change")
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	return printVersion(m, out)
+}
+
+func printVersion(m *migrate.Migrate, out io.Writer) error {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Fprintln(out, "no migrations applied")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if dirty {
+		fmt.Fprintf(out, "version %d (dirty: fix the schema and run force)\n", version)
+		return nil
+	}
+	fmt.Fprintf(out, "version %d\n", version)
+	return nil
+}
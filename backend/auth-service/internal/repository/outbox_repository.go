@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/tracing"
+)
+
+// outboxLockKey - ключ advisory-блокировки: события отправляет одна реплика за раз,
+// поэтому порядок в шине совпадает с порядком записи
+const outboxLockKey = 7310001
+
+type OutboxRepository interface {
+	// Add сохраняет событие вместе с контекстом трассировки ctx
+	Add(ctx context.Context, event *entity.OutboxEvent) error
+	WithTx(tx *sql.Tx) OutboxRepository
+	// PublishPending передает publish до limit неотправленных событий по порядку
+	// и отмечает отправленные. На первой ошибке останавливается, чтобы не нарушить порядок.
+	PublishPending(ctx context.Context, limit int, publish func(event *entity.OutboxEvent) error) (int, error)
+	CountPending(ctx context.Context) (int64, error)
+	// LastID возвращает номер последнего события агрегата или 0, если событий не было
+	LastID(ctx context.Context, aggregateType, aggregateID string) (int64, error)
+}
+
+type SQLOutboxRepository struct {
+	db   DBTX
+	conn *sql.DB
+}
+
+func NewSQLOutboxRepository(db *sql.DB) OutboxRepository {
+	return &SQLOutboxRepository{db: db, conn: db}
+}
+
+func (r *SQLOutboxRepository) WithTx(tx *sql.Tx) OutboxRepository {
+	return &SQLOutboxRepository{db: tx, conn: r.conn}
+}
+
+func (r *SQLOutboxRepository) Add(ctx context.Context, event *entity.OutboxEvent) error {
+	event.TraceContext = tracing.Inject(ctx)
+	var traceContext []byte
+	if event.TraceContext != nil {
+		var err error
+		if traceContext, err = json.Marshal(event.TraceContext); err != nil {
+			return err
+		}
+	}
+
+	return r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload, trace_context) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at",
+		event.AggregateType,
+		event.AggregateID,
+		event.EventType,
+		event.Payload,
+		traceContext,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+func (r *SQLOutboxRepository) CountPending(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox WHERE published_at IS NULL").Scan(&n)
+	return n, err
+}
+
+func (r *SQLOutboxRepository) LastID(ctx context.Context, aggregateType, aggregateID string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(MAX(id), 0) FROM outbox WHERE aggregate_type = $1 AND aggregate_id = $2",
+		aggregateType,
+		aggregateID,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *SQLOutboxRepository) PublishPending(ctx context.Context, limit int, publish func(event *entity.OutboxEvent) error) (int, error) {
+	if r.conn == nil {
+		return 0, errors.New("outbox: PublishPending needs its own transaction")
+	}
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxLockKey).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT id, aggregate_type, aggregate_id, event_type, payload, trace_context, created_at, attempts FROM outbox
+		WHERE published_at IS NULL ORDER BY id LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+	var pending []*entity.OutboxEvent
+	for rows.Next() {
+		var (
+			e            entity.OutboxEvent
+			traceContext []byte
+		)
+		if err := rows.Scan(&e.ID, &e.AggregateType, &e.AggregateID, &e.EventType, &e.Payload, &traceContext, &e.CreatedAt, &e.Attempts); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if traceContext != nil {
+			// Испорченный контекст трассировки не должен задерживать событие
+			json.Unmarshal(traceContext, &e.TraceContext)
+		}
+		pending = append(pending, &e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var publishErr error
+	for _, e := range pending {
+		if publishErr = publish(e); publishErr != nil {
+			if _, err := tx.ExecContext(
+				ctx,
+				"UPDATE outbox SET attempts = attempts + 1, last_error = $1 WHERE id = $2",
+				publishErr.Error(),
+				e.ID,
+			); err != nil {
+				return 0, err
+			}
+			break
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE outbox SET published_at = $1 WHERE id = $2", time.Now(), e.ID); err != nil {
+			return 0, err
+		}
+		published++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
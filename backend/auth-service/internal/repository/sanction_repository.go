@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+)
+
+type SanctionRepository interface {
+	Create(ctx context.Context, sanction *entity.Sanction) error
+	FindByID(ctx context.Context, id uint) (*entity.Sanction, error)
+	FindActive(ctx context.Context, userID uint, now time.Time) ([]*entity.Sanction, error)
+	ListByUser(ctx context.Context, userID uint) ([]*entity.Sanction, error)
+	Lift(ctx context.Context, id uint, liftedBy uint, liftedAt time.Time) error
+	WithTx(tx *sql.Tx) SanctionRepository
+}
+
+type SQLSanctionRepository struct {
+	db DBTX
+}
+
+func NewSQLSanctionRepository(db *sql.DB) SanctionRepository {
+	return &SQLSanctionRepository{db: db}
+}
+
+func (r *SQLSanctionRepository) WithTx(tx *sql.Tx) SanctionRepository {
+	return &SQLSanctionRepository{db: tx}
+}
+
+const sanctionColumns = "id, user_id, type, reason, issued_by, starts_at, expires_at, lifted_at, lifted_by, created_at"
+
+func scanSanction(row interface{ Scan(...interface{}) error }) (*entity.Sanction, error) {
+	var (
+		s         entity.Sanction
+		issuedBy  sql.NullInt64
+		liftedBy  sql.NullInt64
+		expiresAt sql.NullTime
+		liftedAt  sql.NullTime
+	)
+
+	err := row.Scan(&s.ID, &s.UserID, &s.Type, &s.Reason, &issuedBy, &s.StartsAt, &expiresAt, &liftedAt, &liftedBy, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if issuedBy.Valid {
+		id := uint(issuedBy.Int64)
+		s.IssuedBy = &id
+	}
+	if liftedBy.Valid {
+		id := uint(liftedBy.Int64)
+		s.LiftedBy = &id
+	}
+	if expiresAt.Valid {
+		s.ExpiresAt = &expiresAt.Time
+	}
+	if liftedAt.Valid {
+		s.LiftedAt = &liftedAt.Time
+	}
+	return &s, nil
+}
+
+func (r *SQLSanctionRepository) Create(ctx context.Context, s *entity.Sanction) error {
+	return r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO user_sanctions (user_id, type, reason, issued_by, starts_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at",
+		s.UserID,
+		s.Type,
+		s.Reason,
+		s.IssuedBy,
+		s.StartsAt,
+		s.ExpiresAt,
+	).Scan(&s.ID, &s.CreatedAt)
+}
+
+func (r *SQLSanctionRepository) FindByID(ctx context.Context, id uint) (*entity.Sanction, error) {
+	s, err := scanSanction(r.db.QueryRowContext(
+		ctx,
+		"SELECT "+sanctionColumns+" FROM user_sanctions WHERE id = $1",
+		id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRecordNotFound
+	}
+	return s, err
+}
+
+func (r *SQLSanctionRepository) FindActive(ctx context.Context, userID uint, now time.Time) ([]*entity.Sanction, error) {
+	return r.query(
+		ctx,
+		"SELECT "+sanctionColumns+` FROM user_sanctions
+		WHERE user_id = $1 AND lifted_at IS NULL AND starts_at <= $2 AND (expires_at IS NULL OR expires_at > $2)
+		ORDER BY starts_at DESC`,
+		userID,
+		now,
+	)
+}
+
+func (r *SQLSanctionRepository) ListByUser(ctx context.Context, userID uint) ([]*entity.Sanction, error) {
+	return r.query(
+		ctx,
+		"SELECT "+sanctionColumns+" FROM user_sanctions WHERE user_id = $1 ORDER BY created_at DESC",
+		userID,
+	)
+}
+
+func (r *SQLSanctionRepository) Lift(ctx context.Context, id uint, liftedBy uint, liftedAt time.Time) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE user_sanctions SET lifted_at = $1, lifted_by = $2 WHERE id = $3 AND lifted_at IS NULL",
+		liftedAt,
+		liftedBy,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *SQLSanctionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Sanction, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sanctions []*entity.Sanction
+	for rows.Next() {
+		s, err := scanSanction(rows)
+		if err != nil {
+			return nil, err
+		}
+		sanctions = append(sanctions, s)
+	}
+	return sanctions, rows.Err()
+}
@@ -1,14 +1,16 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"time"
 )
 
 type TokenRepository interface {
-	Save(userID uint, token string, expiresAt time.Time) error
-	Find(token string) (uint, time.Time, error)
-	Delete(token string) error
+	Save(ctx context.Context, userID uint, token string, expiresAt time.Time) error
+	Find(ctx context.Context, token string) (uint, time.Time, error)
+	Delete(ctx context.Context, token string) error
+	DeleteByUser(ctx context.Context, userID uint) error
 }
 
 type SQLTokenRepository struct {
@@ -19,8 +21,9 @@ func NewSQLTokenRepository(db *sql.DB) TokenRepository {
 	return &SQLTokenRepository{db: db}
 }
 
-func (r *SQLTokenRepository) Save(userID uint, token string, expiresAt time.Time) error {
-	_, err := r.db.Exec(
+func (r *SQLTokenRepository) Save(ctx context.Context, userID uint, token string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(
+		ctx,
 		"INSERT INTO tokens (user_id, token, expires_at) VALUES ($1, $2, $3)",
 		userID,
 		token,
@@ -29,13 +32,14 @@ func (r *SQLTokenRepository) Save(userID uint, token string, expiresAt time.Time
 	return err
 }
 
-func (r *SQLTokenRepository) Find(token string) (uint, time.Time, error) {
+func (r *SQLTokenRepository) Find(ctx context.Context, token string) (uint, time.Time, error) {
 	var (
 		userID    uint
 		expiresAt time.Time
 	)
 
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(
+		ctx,
 		"SELECT user_id, expires_at FROM tokens WHERE token = $1",
 		token,
 	).Scan(&userID, &expiresAt)
@@ -43,10 +47,20 @@ func (r *SQLTokenRepository) Find(token string) (uint, time.Time, error) {
 	return userID, expiresAt, err
 }
 
-func (r *SQLTokenRepository) Delete(token string) error {
-	_, err := r.db.Exec(
+func (r *SQLTokenRepository) Delete(ctx context.Context, token string) error {
+	_, err := r.db.ExecContext(
+		ctx,
 		"DELETE FROM tokens WHERE token = $1",
 		token,
 	)
 	return err
 }
+
+func (r *SQLTokenRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM tokens WHERE user_id = $1",
+		userID,
+	)
+	return err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX - общее у *sql.DB и *sql.Tx, чтобы один репозиторий работал и внутри транзакции
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// TxManager выполняет fn в одной транзакции. Репозитории подключаются к ней через WithTx.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(tx *sql.Tx) error) error
+}
+
+type SQLTxManager struct {
+	db *sql.DB
+}
+
+func NewSQLTxManager(db *sql.DB) TxManager {
+	return &SQLTxManager{db: db}
+}
+
+func (m *SQLTxManager) WithinTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
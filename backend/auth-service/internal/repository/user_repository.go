@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
@@ -10,25 +11,34 @@ import (
 var ErrRecordNotFound = errors.New("record not found")
 
 type UserRepository interface {
-	FindByUsername(username string) (*entity.User, error)
-	FindByEmail(email string) (*entity.User, error)
-	Create(user *entity.User) error
+	FindByUsername(ctx context.Context, username string) (*entity.User, error)
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	FindByID(ctx context.Context, id uint) (*entity.User, error)
+	Create(ctx context.Context, user *entity.User) error
+	// ListAfter возвращает до limit пользователей с ID больше afterID по возрастанию ID
+	ListAfter(ctx context.Context, afterID uint, limit int) ([]*entity.User, error)
+	WithTx(tx *sql.Tx) UserRepository
 }
 
 type SQLUserRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewSQLUserRepository(db *sql.DB) UserRepository {
 	return &SQLUserRepository{db: db}
 }
 
-func (r *SQLUserRepository) FindByUsername(username string) (*entity.User, error) {
+func (r *SQLUserRepository) WithTx(tx *sql.Tx) UserRepository {
+	return &SQLUserRepository{db: tx}
+}
+
+func (r *SQLUserRepository) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	user := &entity.User{}
-	err := r.db.QueryRow(
-		"SELECT id, username, email, password FROM users WHERE username = $1",
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT id, username, email, role, password FROM users WHERE username = $1",
 		username,
-	).Scan(&user.ID, &user.Username, &user.Email, &user.Password)
+	).Scan(&user.ID, &user.Username, &user.Email, &user.Role, &user.Password)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
@@ -39,12 +49,30 @@ func (r *SQLUserRepository) FindByUsername(username string) (*entity.User, error
 	return user, nil
 }
 
-func (r *SQLUserRepository) FindByEmail(email string) (*entity.User, error) {
+func (r *SQLUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	user := &entity.User{}
-	err := r.db.QueryRow(
-		"SELECT id, username, email, password FROM users WHERE email = $1",
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT id, username, email, role, password FROM users WHERE email = $1",
 		email,
-	).Scan(&user.ID, &user.Username, &user.Email, &user.Password)
+	).Scan(&user.ID, &user.Username, &user.Email, &user.Role, &user.Password)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *SQLUserRepository) FindByID(ctx context.Context, id uint) (*entity.User, error) {
+	user := &entity.User{}
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT id, username, email, role, password FROM users WHERE id = $1",
+		id,
+	).Scan(&user.ID, &user.Username, &user.Email, &user.Role, &user.Password)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
@@ -55,11 +83,35 @@ func (r *SQLUserRepository) FindByEmail(email string) (*entity.User, error) {
 	return user, nil
 }
 
-func (r *SQLUserRepository) Create(user *entity.User) error {
-	return r.db.QueryRow(
-		"INSERT INTO users (username, email, password) VALUES ($1, $2, $3) RETURNING id",
+func (r *SQLUserRepository) Create(ctx context.Context, user *entity.User) error {
+	return r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO users (username, email, password) VALUES ($1, $2, $3) RETURNING id, role",
 		user.Username,
 		user.Email,
 		user.Password,
-	).Scan(&user.ID)
+	).Scan(&user.ID, &user.Role)
+}
+
+func (r *SQLUserRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]*entity.User, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		"SELECT id, username, email, role FROM users WHERE id > $1 ORDER BY id LIMIT $2",
+		afterID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		user := &entity.User{}
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Role); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
 }
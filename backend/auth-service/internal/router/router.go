@@ -7,18 +7,34 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/metrics"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/health"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/tracing"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRouter(authController *controller.AuthController) *gin.Engine {
+func SetupRouter(
+	authController *controller.AuthController,
+	adminController *controller.AdminController,
+	adminMiddleware gin.HandlerFunc,
+	syncController *controller.SyncController,
+	serviceMiddleware gin.HandlerFunc,
+	rateLimit gin.HandlerFunc,
+	allowedOrigins []string,
+	probes *health.Health,
+) *gin.Engine {
 	r := gin.Default()
+	// Спан запроса продолжает трассу из заголовка traceparent
+	r.Use(otelgin.Middleware("auth-service", otelgin.WithFilter(tracing.Traced)))
+	r.Use(metrics.HTTP())
 
 	// Настройка CORS с более строгими параметрами
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"},
+		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Requested-With", "Accept"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Requested-With", "Accept", "traceparent", "tracestate"},
 		ExposeHeaders:    []string{"Content-Length", "Authorization"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -26,9 +42,13 @@ func SetupRouter(authController *controller.AuthController) *gin.Engine {
 
 	// Обработка OPTIONS запросов
 	r.OPTIONS("/*any", func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "http://localhost:3000")
+		for _, origin := range allowedOrigins {
+			if origin == c.GetHeader("Origin") {
+				c.Header("Access-Control-Allow-Origin", origin)
+			}
+		}
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Requested-With, Accept")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Requested-With, Accept, traceparent, tracestate")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Status(200)
 	})
@@ -43,7 +63,7 @@ func SetupRouter(authController *controller.AuthController) *gin.Engine {
 		c.Next()
 
 		// Log only when path is not healthcheck
-		if path != "/health" {
+		if path != "/health" && path != "/livez" && path != "/readyz" && path != "/metrics" {
 			latency := time.Since(start)
 			clientIP := c.ClientIP()
 			method := c.Request.Method
@@ -72,6 +92,7 @@ func SetupRouter(authController *controller.AuthController) *gin.Engine {
 
 	// Группа API для аутентификации
 	authGroup := r.Group("/api/auth")
+	authGroup.Use(rateLimit)
 	{
 		authGroup.POST("/login", func(c *gin.Context) {
 			log.Println("Login request received")
@@ -89,14 +110,28 @@ func SetupRouter(authController *controller.AuthController) *gin.Engine {
 		})
 	}
 
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"message": "Auth service is running",
-			"time":    time.Now().Format(time.RFC3339),
-		})
-	})
+	// Администрирование: санкции против пользователей
+	adminGroup := r.Group("/api/admin")
+	adminGroup.Use(adminMiddleware)
+	{
+		adminGroup.GET("/users/:id/sanctions", adminController.ListSanctions)
+		adminGroup.POST("/users/:id/sanctions", adminController.ApplySanction)
+		adminGroup.DELETE("/sanctions/:id", adminController.LiftSanction)
+	}
+
+	// Снимки пользователей для сервисов, пропустивших события
+	internalGroup := r.Group("/internal")
+	internalGroup.Use(serviceMiddleware)
+	{
+		internalGroup.GET("/users", syncController.ListUsers)
+		internalGroup.GET("/users/:id", syncController.GetUser)
+	}
+
+	// Health check endpoints; /health оставлен для старых проверок и равен /readyz
+	r.GET("/livez", gin.WrapF(probes.Live))
+	r.GET("/readyz", gin.WrapF(probes.Ready))
+	r.GET("/health", gin.WrapF(probes.Ready))
+	r.GET("/metrics", metrics.Handler())
 
 	// Обработка 404 ошибок
 	r.NoRoute(func(c *gin.Context) {
@@ -1,13 +1,15 @@
 package service
 
 import (
+	"context"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/metrics"
 	"github.com/lera-guryan2222/forum/backend/auth-service/internal/usecase"
 )
 
 type AuthService interface {
-	Register(req usecase.RegisterRequest) (*usecase.RegisterResponse, error)
-	Login(req usecase.LoginRequest) (*usecase.LoginResponse, error)
-	Refresh(req usecase.RefreshRequest) (*usecase.RefreshResponse, error)
+	Register(ctx context.Context, req usecase.RegisterRequest) (*usecase.RegisterResponse, error)
+	Login(ctx context.Context, req usecase.LoginRequest) (*usecase.LoginResponse, error)
+	Refresh(ctx context.Context, req usecase.RefreshRequest) (*usecase.RefreshResponse, error)
 }
 
 type authService struct {
@@ -18,14 +20,22 @@ type authService struct {
 func NewAuthService(uc usecase.AuthUsecase) AuthService {
 	return &authService{uc: uc}
 }
-func (s *authService) Register(req usecase.RegisterRequest) (*usecase.RegisterResponse, error) {
-	return s.uc.Register(req)
+func (s *authService) Register(ctx context.Context, req usecase.RegisterRequest) (*usecase.RegisterResponse, error) {
+	resp, err := s.uc.Register(ctx, req)
+	if err == nil {
+		metrics.Registrations.Inc()
+	}
+	return resp, err
 }
 
-func (s *authService) Login(req usecase.LoginRequest) (*usecase.LoginResponse, error) {
-	return s.uc.Login(req)
+func (s *authService) Login(ctx context.Context, req usecase.LoginRequest) (*usecase.LoginResponse, error) {
+	resp, err := s.uc.Login(ctx, req)
+	metrics.Logins.WithLabelValues(metrics.Result(err)).Inc()
+	return resp, err
 }
 
-func (s *authService) Refresh(req usecase.RefreshRequest) (*usecase.RefreshResponse, error) {
-	return s.uc.Refresh(req)
+func (s *authService) Refresh(ctx context.Context, req usecase.RefreshRequest) (*usecase.RefreshResponse, error) {
+	resp, err := s.uc.Refresh(ctx, req)
+	metrics.Refreshes.WithLabelValues(metrics.Result(err)).Inc()
+	return resp, err
 }
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/events"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// outboxBatchSize - сколько событий отправляется за один проход
+const outboxBatchSize = 100
+
+// OutboxRelay переносит события из таблицы outbox в шину. Доставка "хотя бы раз":
+// если отметка об отправке не сохранилась, событие уйдет повторно с тем же ID.
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository
+	publisher events.Publisher
+	logger    *log.Logger
+}
+
+func NewOutboxRelay(outbox repository.OutboxRepository, publisher events.Publisher, logger *log.Logger) *OutboxRelay {
+	return &OutboxRelay{outbox: outbox, publisher: publisher, logger: logger}
+}
+
+// Run отправляет накопленные события каждые interval до отмены ctx
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayPending(ctx); err != nil {
+				r.logger.Printf("Outbox relay failed: %v", err)
+			}
+		}
+	}
+}
+
+// CheckBacklog - проверка готовности: больше max неотправленных событий
+// означает, что шина недоступна или relay не успевает
+func (r *OutboxRelay) CheckBacklog(max int64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		n, err := r.outbox.CountPending(ctx)
+		if err != nil {
+			return err
+		}
+		if n > max {
+			return fmt.Errorf("%d events pending in outbox, limit %d", n, max)
+		}
+		return nil
+	}
+}
+
+// RelayPending отправляет все неотправленные события и возвращает их число
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.outbox.PublishPending(ctx, outboxBatchSize, func(e *entity.OutboxEvent) error {
+			return r.publish(ctx, e)
+		})
+		total += n
+		if err != nil || n < outboxBatchSize {
+			return total, err
+		}
+	}
+}
+
+// publish отправляет событие в трассе запроса, который его записал. Версией служит
+// номер записи outbox: событие добавляется в транзакции, которая уже держит блокировку
+// измененной строки, поэтому номера событий одной записи растут в порядке фиксации.
+func (r *OutboxRelay) publish(ctx context.Context, e *entity.OutboxEvent) error {
+	ctx = tracing.Extract(ctx, e.TraceContext)
+	ctx, span := otel.Tracer("auth-service/outbox").Start(ctx, "publish "+e.EventType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+	defer span.End()
+
+	err := r.publisher.Publish(ctx, &events.Event{
+		ID:          fmt.Sprintf("auth-service:%d", e.ID),
+		Type:        e.EventType,
+		AggregateID: e.AggregateType + ":" + e.AggregateID,
+		OccurredAt:  e.CreatedAt,
+		Version:     e.ID,
+		Payload:     e.Payload,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/usecase"
+)
+
+type SanctionService interface {
+	Apply(ctx context.Context, issuerID, userID uint, req usecase.ApplySanctionRequest) (*entity.Sanction, error)
+	Lift(ctx context.Context, issuerID, sanctionID uint) error
+	ListByUser(ctx context.Context, userID uint) ([]*entity.Sanction, error)
+}
+
+type sanctionService struct {
+	uc usecase.SanctionUsecase
+}
+
+func NewSanctionService(uc usecase.SanctionUsecase) SanctionService {
+	return &sanctionService{uc: uc}
+}
+
+func (s *sanctionService) Apply(ctx context.Context, issuerID, userID uint, req usecase.ApplySanctionRequest) (*entity.Sanction, error) {
+	return s.uc.Apply(ctx, issuerID, userID, req)
+}
+
+func (s *sanctionService) Lift(ctx context.Context, issuerID, sanctionID uint) error {
+	return s.uc.Lift(ctx, issuerID, sanctionID)
+}
+
+func (s *sanctionService) ListByUser(ctx context.Context, userID uint) ([]*entity.Sanction, error) {
+	return s.uc.ListByUser(ctx, userID)
+}
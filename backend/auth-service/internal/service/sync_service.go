@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/usecase"
+)
+
+type SyncService interface {
+	Snapshot(ctx context.Context, userID uint) (*entity.UserSnapshot, error)
+	Snapshots(ctx context.Context, afterID uint, limit int) ([]*entity.UserSnapshot, error)
+}
+
+type syncService struct {
+	uc usecase.SyncUsecase
+}
+
+func NewSyncService(uc usecase.SyncUsecase) SyncService {
+	return &syncService{uc: uc}
+}
+
+func (s *syncService) Snapshot(ctx context.Context, userID uint) (*entity.UserSnapshot, error) {
+	return s.uc.Snapshot(ctx, userID)
+}
+
+func (s *syncService) Snapshots(ctx context.Context, afterID uint, limit int) ([]*entity.UserSnapshot, error) {
+	return s.uc.Snapshots(ctx, afterID, limit)
+}
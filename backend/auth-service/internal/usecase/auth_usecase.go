@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"time"
 
@@ -11,26 +13,38 @@ import (
 )
 
 type AuthUsecase interface {
-	Login(request LoginRequest) (*LoginResponse, error)
-	Register(request RegisterRequest) (*RegisterResponse, error)
-	Refresh(request RefreshRequest) (*RefreshResponse, error)
+	Login(ctx context.Context, request LoginRequest) (*LoginResponse, error)
+	Register(ctx context.Context, request RegisterRequest) (*RegisterResponse, error)
+	Refresh(ctx context.Context, request RefreshRequest) (*RefreshResponse, error)
 }
 
 type authUsecase struct {
 	userRepo     repository.UserRepository
 	tokenRepo    repository.TokenRepository
+	sanctionRepo repository.SanctionRepository
+	outboxRepo   repository.OutboxRepository
+	txManager    repository.TxManager
 	tokenManager auth.TokenManager
+	bcryptCost   int
 }
 
 func NewAuthUsecase(
 	userRepo repository.UserRepository,
 	tokenRepo repository.TokenRepository,
+	sanctionRepo repository.SanctionRepository,
+	outboxRepo repository.OutboxRepository,
+	txManager repository.TxManager,
 	tokenManager auth.TokenManager,
+	bcryptCost int,
 ) AuthUsecase {
 	return &authUsecase{
 		userRepo:     userRepo,
 		tokenRepo:    tokenRepo,
+		sanctionRepo: sanctionRepo,
+		outboxRepo:   outboxRepo,
+		txManager:    txManager,
 		tokenManager: tokenManager,
+		bcryptCost:   bcryptCost,
 	}
 }
 
@@ -68,19 +82,26 @@ type (
 	}
 )
 
-func (uc *authUsecase) Login(req LoginRequest) (*LoginResponse, error) {
-	user, err := uc.userRepo.FindByEmail(req.Email)
+func (uc *authUsecase) Login(ctx context.Context, req LoginRequest) (*LoginResponse, error) {
+	user, err := uc.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return nil, errors.New("invalid credentials")
 		}
 		return nil, err
 	}
+	if user == nil {
+		return nil, errors.New("invalid credentials")
+	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		return nil, errors.New("invalid credentials")
 	}
 
+	if err := checkLoginAllowed(ctx, uc.sanctionRepo, user.ID); err != nil {
+		return nil, err
+	}
+
 	accessToken, err := uc.tokenManager.GenerateAccessToken(user.ID)
 	if err != nil {
 		return nil, err
@@ -91,7 +112,7 @@ func (uc *authUsecase) Login(req LoginRequest) (*LoginResponse, error) {
 		return nil, err
 	}
 
-	if err := uc.tokenRepo.Save(user.ID, refreshToken, expiresAt); err != nil {
+	if err := uc.tokenRepo.Save(ctx, user.ID, refreshToken, expiresAt); err != nil {
 		return nil, err
 	}
 
@@ -102,22 +123,22 @@ func (uc *authUsecase) Login(req LoginRequest) (*LoginResponse, error) {
 	}, nil
 }
 
-func (uc *authUsecase) Register(req RegisterRequest) (*RegisterResponse, error) {
+func (uc *authUsecase) Register(ctx context.Context, req RegisterRequest) (*RegisterResponse, error) {
 	if req.Username == "" || req.Email == "" || req.Password == "" {
 		return nil, errors.New("all fields are required")
 	}
 
-	existingUser, _ := uc.userRepo.FindByUsername(req.Username)
+	existingUser, _ := uc.userRepo.FindByUsername(ctx, req.Username)
 	if existingUser != nil {
 		return nil, errors.New("username already exists")
 	}
 
-	existingEmailUser, _ := uc.userRepo.FindByEmail(req.Email)
+	existingEmailUser, _ := uc.userRepo.FindByEmail(ctx, req.Email)
 	if existingEmailUser != nil {
 		return nil, errors.New("email already exists")
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), uc.bcryptCost)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +149,18 @@ func (uc *authUsecase) Register(req RegisterRequest) (*RegisterResponse, error)
 		Password: string(hashedPassword),
 	}
 
-	if err := uc.userRepo.Create(user); err != nil {
+	// Пользователь и событие о нем фиксируются вместе: forum-service узнает о каждой регистрации
+	err = uc.txManager.WithinTx(ctx, func(tx *sql.Tx) error {
+		if err := uc.userRepo.WithTx(tx).Create(ctx, user); err != nil {
+			return err
+		}
+		event, err := userEvent(entity.EventUserRegistered, user)
+		if err != nil {
+			return err
+		}
+		return uc.outboxRepo.WithTx(tx).Add(ctx, event)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -142,7 +174,7 @@ func (uc *authUsecase) Register(req RegisterRequest) (*RegisterResponse, error)
 		return nil, err
 	}
 
-	if err := uc.tokenRepo.Save(user.ID, refreshToken, expiresAt); err != nil {
+	if err := uc.tokenRepo.Save(ctx, user.ID, refreshToken, expiresAt); err != nil {
 		return nil, err
 	}
 
@@ -153,8 +185,8 @@ func (uc *authUsecase) Register(req RegisterRequest) (*RegisterResponse, error)
 	}, nil
 }
 
-func (uc *authUsecase) Refresh(req RefreshRequest) (*RefreshResponse, error) {
-	userID, expiresAt, err := uc.tokenRepo.Find(req.RefreshToken)
+func (uc *authUsecase) Refresh(ctx context.Context, req RefreshRequest) (*RefreshResponse, error) {
+	userID, expiresAt, err := uc.tokenRepo.Find(ctx, req.RefreshToken)
 	if err != nil {
 		return nil, errors.New("invalid refresh token")
 	}
@@ -163,6 +195,17 @@ func (uc *authUsecase) Refresh(req RefreshRequest) (*RefreshResponse, error) {
 		return nil, errors.New("refresh token expired")
 	}
 
+	if err := checkLoginAllowed(ctx, uc.sanctionRepo, userID); err != nil {
+		var sanctionErr *SanctionError
+		if errors.As(err, &sanctionErr) {
+			// Заблокированный пользователь теряет все выданные refresh-токены
+			if revokeErr := uc.tokenRepo.DeleteByUser(ctx, userID); revokeErr != nil {
+				return nil, revokeErr
+			}
+		}
+		return nil, err
+	}
+
 	newAccessToken, err := uc.tokenManager.GenerateAccessToken(userID)
 	if err != nil {
 		return nil, err
@@ -173,11 +216,11 @@ func (uc *authUsecase) Refresh(req RefreshRequest) (*RefreshResponse, error) {
 		return nil, err
 	}
 
-	if err := uc.tokenRepo.Delete(req.RefreshToken); err != nil {
+	if err := uc.tokenRepo.Delete(ctx, req.RefreshToken); err != nil {
 		return nil, err
 	}
 
-	if err := uc.tokenRepo.Save(userID, newRefreshToken, newExpiresAt); err != nil {
+	if err := uc.tokenRepo.Save(ctx, userID, newRefreshToken, newExpiresAt); err != nil {
 		return nil, err
 	}
 
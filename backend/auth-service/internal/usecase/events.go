@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+)
+
+func userEvent(eventType string, user *entity.User) (*entity.OutboxEvent, error) {
+	return newEvent(eventType, user.ID, userPayload(user))
+}
+
+func sanctionEvent(eventType string, s *entity.Sanction) (*entity.OutboxEvent, error) {
+	return newEvent(eventType, s.UserID, sanctionPayload(s))
+}
+
+func userPayload(user *entity.User) entity.UserEventPayload {
+	return entity.UserEventPayload{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+	}
+}
+
+func sanctionPayload(s *entity.Sanction) entity.SanctionEventPayload {
+	return entity.SanctionEventPayload{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Type:      s.Type,
+		Reason:    s.Reason,
+		StartsAt:  s.StartsAt,
+		ExpiresAt: s.ExpiresAt,
+		LiftedAt:  s.LiftedAt,
+	}
+}
+
+// newEvent - события о пользователе и его санкциях упорядочены по одному агрегату
+func newEvent(eventType string, userID uint, payload interface{}) (*entity.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.OutboxEvent{
+		AggregateType: entity.AggregateUser,
+		AggregateID:   strconv.FormatUint(uint64(userID), 10),
+		EventType:     eventType,
+		Payload:       data,
+	}, nil
+}
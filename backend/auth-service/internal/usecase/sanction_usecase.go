@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/repository"
+)
+
+type SanctionUsecase interface {
+	Apply(ctx context.Context, issuerID, userID uint, request ApplySanctionRequest) (*entity.Sanction, error)
+	Lift(ctx context.Context, issuerID, sanctionID uint) error
+	ListByUser(ctx context.Context, userID uint) ([]*entity.Sanction, error)
+}
+
+type sanctionUsecase struct {
+	userRepo     repository.UserRepository
+	tokenRepo    repository.TokenRepository
+	sanctionRepo repository.SanctionRepository
+	outboxRepo   repository.OutboxRepository
+	txManager    repository.TxManager
+}
+
+func NewSanctionUsecase(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	sanctionRepo repository.SanctionRepository,
+	outboxRepo repository.OutboxRepository,
+	txManager repository.TxManager,
+) SanctionUsecase {
+	return &sanctionUsecase{
+		userRepo:     userRepo,
+		tokenRepo:    tokenRepo,
+		sanctionRepo: sanctionRepo,
+		outboxRepo:   outboxRepo,
+		txManager:    txManager,
+	}
+}
+
+type ApplySanctionRequest struct {
+	Type      string     `json:"type"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrSanctionNotFound = errors.New("sanction not found")
+	// ErrValidation оборачивает ошибки в самом запросе на санкцию
+	ErrValidation = errors.New("invalid sanction")
+	// ErrForbidden - выдавший санкцию не старше по роли, чем ее получатель
+	ErrForbidden = errors.New("cannot sanction a user with the same or a higher role")
+)
+
+// SanctionError возвращается, когда действие запрещено активной санкцией
+type SanctionError struct {
+	Sanction *entity.Sanction
+}
+
+func (e *SanctionError) Error() string {
+	s := e.Sanction
+	state := "suspended"
+	switch s.Type {
+	case entity.SanctionBan:
+		return fmt.Sprintf("account banned: %s", s.Reason)
+	case entity.SanctionMute:
+		state = "muted"
+	}
+	if s.ExpiresAt != nil {
+		return fmt.Sprintf("account %s until %s: %s", state, s.ExpiresAt.Format(time.RFC3339), s.Reason)
+	}
+	return fmt.Sprintf("account %s: %s", state, s.Reason)
+}
+
+// checkLoginAllowed возвращает *SanctionError, если у пользователя есть активный бан или блокировка
+func checkLoginAllowed(ctx context.Context, repo repository.SanctionRepository, userID uint) error {
+	sanctions, err := repo.FindActive(ctx, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, s := range sanctions {
+		if s.BlocksLogin() {
+			return &SanctionError{Sanction: s}
+		}
+	}
+	return nil
+}
+
+func (uc *sanctionUsecase) Apply(ctx context.Context, issuerID, userID uint, req ApplySanctionRequest) (*entity.Sanction, error) {
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrValidation)
+	}
+
+	now := time.Now()
+	switch req.Type {
+	case entity.SanctionBan:
+		if req.ExpiresAt != nil {
+			return nil, fmt.Errorf("%w: ban is permanent, use suspension for a timed block", ErrValidation)
+		}
+	case entity.SanctionSuspension:
+		if req.ExpiresAt == nil {
+			return nil, fmt.Errorf("%w: suspension requires expires_at", ErrValidation)
+		}
+	case entity.SanctionMute:
+	default:
+		return nil, fmt.Errorf("%w: unknown sanction type %q", ErrValidation, req.Type)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrValidation)
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	issuer, err := uc.userRepo.FindByID(ctx, issuerID)
+	if err != nil {
+		return nil, err
+	}
+	// Администраторов не может наказать никто, модераторов - только администратор
+	if issuer == nil || entity.RoleRank(user.Role) >= entity.RoleRank(issuer.Role) {
+		return nil, ErrForbidden
+	}
+
+	sanction := &entity.Sanction{
+		UserID:    userID,
+		Type:      req.Type,
+		Reason:    req.Reason,
+		IssuedBy:  &issuerID,
+		StartsAt:  now,
+		ExpiresAt: req.ExpiresAt,
+	}
+	err = uc.txManager.WithinTx(ctx, func(tx *sql.Tx) error {
+		if err := uc.sanctionRepo.WithTx(tx).Create(ctx, sanction); err != nil {
+			return err
+		}
+		eventType := entity.EventUserMuted
+		if sanction.BlocksLogin() {
+			eventType = entity.EventUserBanned
+		}
+		event, err := sanctionEvent(eventType, sanction)
+		if err != nil {
+			return err
+		}
+		return uc.outboxRepo.WithTx(tx).Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sanction.BlocksLogin() {
+		if err := uc.tokenRepo.DeleteByUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return sanction, nil
+}
+
+func (uc *sanctionUsecase) Lift(ctx context.Context, issuerID, sanctionID uint) error {
+	err := uc.txManager.WithinTx(ctx, func(tx *sql.Tx) error {
+		sanctions := uc.sanctionRepo.WithTx(tx)
+		if err := sanctions.Lift(ctx, sanctionID, issuerID, time.Now()); err != nil {
+			return err
+		}
+		sanction, err := sanctions.FindByID(ctx, sanctionID)
+		if err != nil {
+			return err
+		}
+		event, err := sanctionEvent(entity.EventSanctionLifted, sanction)
+		if err != nil {
+			return err
+		}
+		return uc.outboxRepo.WithTx(tx).Add(ctx, event)
+	})
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return ErrSanctionNotFound
+	}
+	return err
+}
+
+func (uc *sanctionUsecase) ListByUser(ctx context.Context, userID uint) ([]*entity.Sanction, error) {
+	return uc.sanctionRepo.ListByUser(ctx, userID)
+}
+
+var _ SanctionUsecase = (*sanctionUsecase)(nil)
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/auth-service/internal/repository"
+)
+
+// SyncUsecase отдает снимки пользователей сервисам, которые держат их копию
+// по событиям и могли пропустить часть из них
+type SyncUsecase interface {
+	Snapshot(ctx context.Context, userID uint) (*entity.UserSnapshot, error)
+	Snapshots(ctx context.Context, afterID uint, limit int) ([]*entity.UserSnapshot, error)
+}
+
+type syncUsecase struct {
+	userRepo     repository.UserRepository
+	sanctionRepo repository.SanctionRepository
+	outboxRepo   repository.OutboxRepository
+}
+
+func NewSyncUsecase(
+	userRepo repository.UserRepository,
+	sanctionRepo repository.SanctionRepository,
+	outboxRepo repository.OutboxRepository,
+) SyncUsecase {
+	return &syncUsecase{
+		userRepo:     userRepo,
+		sanctionRepo: sanctionRepo,
+		outboxRepo:   outboxRepo,
+	}
+}
+
+func (uc *syncUsecase) Snapshot(ctx context.Context, userID uint) (*entity.UserSnapshot, error) {
+	return uc.snapshot(ctx, userID)
+}
+
+func (uc *syncUsecase) Snapshots(ctx context.Context, afterID uint, limit int) ([]*entity.UserSnapshot, error) {
+	users, err := uc.userRepo.ListAfter(ctx, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]*entity.UserSnapshot, 0, len(users))
+	for _, user := range users {
+		snapshot, err := uc.snapshot(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// snapshot читает версию раньше данных: снимок не старше своей версии, и событие
+// с большим номером потребитель все равно применит поверх него
+func (uc *syncUsecase) snapshot(ctx context.Context, userID uint) (*entity.UserSnapshot, error) {
+	version, err := uc.outboxRepo.LastID(ctx, entity.AggregateUser, strconv.FormatUint(uint64(userID), 10))
+	if err != nil {
+		return nil, err
+	}
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	sanctions, err := uc.sanctionRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &entity.UserSnapshot{
+		User:      userPayload(user),
+		Sanctions: make([]entity.SanctionEventPayload, 0, len(sanctions)),
+		Version:   version,
+	}
+	for _, s := range sanctions {
+		snapshot.Sanctions = append(snapshot.Sanctions, sanctionPayload(s))
+	}
+	return snapshot, nil
+}
+
+var _ SyncUsecase = (*syncUsecase)(nil)
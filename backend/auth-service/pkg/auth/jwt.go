@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -9,6 +10,7 @@ import (
 type TokenManager interface {
 	GenerateAccessToken(userID uint) (string, error)
 	GenerateRefreshToken() (string, time.Time, error) // Изменено
+	ParseAccessToken(token string) (uint, error)
 }
 
 type tokenManager struct {
@@ -54,3 +56,27 @@ func (tm *tokenManager) GenerateAccessToken(userID uint) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(tm.accessTokenSecret))
 }
+
+// ParseAccessToken проверяет подпись access-токена и возвращает ID пользователя
+func (tm *tokenManager) ParseAccessToken(tokenString string) (uint, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(tm.accessTokenSecret), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return 0, errors.New("invalid token")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok || userID <= 0 {
+		return 0, errors.New("invalid user_id claim")
+	}
+	return uint(userID), nil
+}
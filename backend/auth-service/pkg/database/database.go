@@ -38,11 +38,16 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
+	"github.com/XSAM/otelsql"
+	"github.com/lera-guryan2222/forum/backend/auth-service/pkg/tracing"
 	_ "github.com/lib/pq" // драйвер для PostgreSQL
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 type Config struct {
@@ -58,7 +63,17 @@ func ConnectWithConfig(config Config) (*sql.DB, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
 
-	db, err := sql.Open("postgres", connStr)
+	// Каждый запрос становится спаном трассы, в рамках которой он выполняется
+	db, err := otelsql.Open("postgres", connStr,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return tracing.HasParent(ctx)
+			},
+		}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %v", err)
 	}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// InProcessBus доставляет события подписчикам того же процесса синхронно.
+// Используется, когда внешний брокер не настроен.
+type InProcessBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewInProcessBus() *InProcessBus {
+	return &InProcessBus{handlers: make(map[string][]Handler)}
+}
+
+// Publish вызывает обработчики по очереди и возвращает первую ошибку, чтобы
+// отправитель повторил событие позже
+func (b *InProcessBus) Publish(ctx context.Context, event *Event) error {
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, handle := range handlers {
+		if err := handle(ctx, event); err != nil {
+			return fmt.Errorf("handle %s %s: %w", event.Type, event.ID, err)
+		}
+	}
+	return nil
+}
+
+func (b *InProcessBus) Subscribe(eventType string, handler Handler) error {
+	b.mu.Lock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+	b.mu.Unlock()
+	return nil
+}
+
+// Broadcast совпадает с Subscribe: в одном процессе все подписчики и так получают событие
+func (b *InProcessBus) Broadcast(eventType string, handler Handler) error {
+	return b.Subscribe(eventType, handler)
+}
+
+func (b *InProcessBus) Close() error {
+	return nil
+}
+
+var _ Bus = (*InProcessBus)(nil)
@@ -0,0 +1,178 @@
+// Package health отдает /livez и /readyz: живость процесса и готовность
+// принимать трафик по результатам проверок зависимостей
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusDraining = "draining"
+	// StatusDegraded - упала только необязательная проверка, трафик принимается
+	StatusDegraded = "degraded"
+)
+
+// CheckFunc проверяет одну зависимость; ctx ограничен таймаутом проверки
+type CheckFunc func(ctx context.Context) error
+
+type checker struct {
+	name     string
+	check    CheckFunc
+	optional bool
+}
+
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+	// Optional - результат виден в отчете, но на готовность не влияет
+	Optional bool `json:"optional,omitempty"`
+}
+
+type Report struct {
+	Status    string        `json:"status"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []CheckResult `json:"checks,omitempty"`
+}
+
+// Health выполняет проверки не чаще раза в cacheTTL: частые пробы нескольких
+// балансировщиков получают сохраненный отчет и не нагружают базу.
+// Одновременные пробы после истечения кэша ждут одну общую проверку.
+type Health struct {
+	ready   func() bool
+	ttl     time.Duration
+	timeout time.Duration
+
+	checkers []checker
+
+	mu     sync.Mutex
+	report *Report
+}
+
+// New создает набор проверок. ready - признак того, что сервис не
+// останавливается; пока он false, /readyz сразу отвечает 503.
+func New(ready func() bool, cacheTTL, checkTimeout time.Duration) *Health {
+	return &Health{ready: ready, ttl: cacheTTL, timeout: checkTimeout}
+}
+
+// Add регистрирует проверку; вызывается до начала обслуживания запросов
+func (h *Health) Add(name string, check CheckFunc) {
+	h.checkers = append(h.checkers, checker{name: name, check: check})
+}
+
+// AddOptional регистрирует проверку, отказ которой только отмечается в отчете.
+// Подходит для зависимостей, без которых сервис продолжает обслуживать запросы:
+// снятие всех реплик с балансировки из-за них сделало бы только хуже.
+func (h *Health) AddOptional(name string, check CheckFunc) {
+	h.checkers = append(h.checkers, checker{name: name, check: check, optional: true})
+}
+
+// Check возвращает отчет о зависимостях, при необходимости обновляя его
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.report != nil && time.Since(h.report.CheckedAt) < h.ttl {
+		return *h.report
+	}
+
+	report := Report{Status: StatusOK, CheckedAt: time.Now(), Checks: make([]CheckResult, len(h.checkers))}
+	var wg sync.WaitGroup
+	for i, c := range h.checkers {
+		wg.Add(1)
+		go func(i int, c checker) {
+			defer wg.Done()
+			report.Checks[i] = h.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		switch {
+		case result.Status == StatusOK:
+		case !result.Optional:
+			report.Status = StatusFailing
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	h.report = &report
+	return report
+}
+
+func (h *Health) run(ctx context.Context, c checker) CheckResult {
+	// Проба могла оборваться, а отчет попадет в кэш для остальных
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.check(ctx)
+	result := CheckResult{Name: c.name, Status: StatusOK, DurationMS: time.Since(start).Milliseconds(), Optional: c.optional}
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Live - процесс жив и обслуживает запросы. Зависимости здесь не проверяются:
+// их отказ не лечится перезапуском.
+func (h *Health) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Report{Status: StatusOK, CheckedAt: time.Now()})
+}
+
+// Ready - сервис готов принимать трафик: не останавливается и все обязательные проверки прошли
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	if !h.ready() {
+		writeJSON(w, http.StatusServiceUnavailable, Report{Status: StatusDraining, CheckedAt: time.Now()})
+		return
+	}
+
+	report := h.Check(r.Context())
+	status := http.StatusOK
+	if report.Status == StatusFailing {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// Ping проверяет соединение с базой
+func Ping(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// HTTP проверяет, что url отвечает статусом 2xx
+func HTTP(client *http.Client, url string) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s returned %s", url, resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+// Package tracing настраивает OpenTelemetry: провайдер спанов, экспорт
+// и распространение контекста в формате W3C traceparent
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+type Config struct {
+	ServiceName string
+	// Exporter - none, stdout или otlp
+	Exporter string
+	// Endpoint - адрес OTLP/HTTP коллектора, например http://localhost:4318
+	Endpoint string
+	// SampleRatio - доля новых трасс, которые записываются. Решение вызывающего
+	// сервиса из traceparent соблюдается всегда.
+	SampleRatio float64
+}
+
+// Setup устанавливает глобальные провайдер и propagator. Заголовки traceparent
+// передаются дальше и при Exporter = none, чтобы не рвать трассы соседей.
+// shutdown отправляет накопленные спаны и вызывается при остановке сервиса.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Inject возвращает контекст трассировки ctx в виде заголовков traceparent/tracestate
+// для сохранения вместе с событием; nil, если ctx не относится к трассе
+func Inject(ctx context.Context) map[string]string {
+	if !HasParent(ctx) {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// Extract восстанавливает контекст трассировки, сохраненный Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// HasParent сообщает, идет ли ctx в рамках трассы. Спаны запросов к базе
+// создаются только внутри нее: фоновые опросы не порождают отдельных трасс.
+func HasParent(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
+
+// Traced - фильтр для middleware: пробы и сбор метрик вызываются часто
+// и в трассах только мешают
+func Traced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/health", "/livez", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
@@ -1,56 +1,48 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/config"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/delivery"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/metrics"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/migrations"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/router"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/auth"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/events"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/health"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/lifecycle"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func Connect() (*gorm.DB, error) {
-	host := os.Getenv("DB_HOST")
-	if host == "" {
-		host = "localhost"
-	}
-
-	user := os.Getenv("DB_USER")
-	if user == "" {
-		user = "user"
-	}
-
-	password := os.Getenv("DB_PASSWORD")
-	if password == "" {
-		password = "postgres"
-	}
-
-	dbname := os.Getenv("DB_NAME")
-	if dbname == "" {
-		dbname = "forum"
-	}
-
-	port := os.Getenv("DB_PORT")
-	if port == "" {
-		port = "5432"
-	}
-
+func Connect(cfg config.DB) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname,
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, fmt.Errorf("database connection error: %w", err)
 	}
+	if err := db.Use(tracing.GORM()); err != nil {
+		return nil, fmt.Errorf("database tracing error: %w", err)
+	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -71,37 +63,227 @@ func Connect() (*gorm.DB, error) {
 func main() {
 	logger := log.New(os.Stdout, "[FORUM] ", log.LstdFlags|log.Lshortfile)
 
-	db, err := Connect()
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	db, err := Connect(cfg.DB)
 	if err != nil {
 		logger.Fatalf("Database connection failed: %v", err)
 	}
-	defer func() {
-		sqlDB, _ := db.DB()
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatalf("Database instance error: %v", err)
+	}
+
+	if len(args) > 0 {
+		runCommand(sqlDB, args, logger)
 		sqlDB.Close()
-	}()
+		return
+	}
 
-	if err := autoMigrate(db); err != nil {
-		logger.Fatalf("Migration failed: %v", err)
+	if cfg.Migrations.Auto {
+		if err := migrations.Up(sqlDB); err != nil {
+			logger.Fatalf("Migration failed: %v", err)
+		}
 	}
 
 	// Инициализация репозиториев
 	postRepo := repository.NewPostRepository(db)
 	userRepo := repository.NewUserRepository(db) // Добавьте реализацию
+	sanctionRepo := repository.NewSanctionRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	mentionRepo := repository.NewMentionRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	digestRepo := repository.NewDigestRepository(db)
+	readRepo := repository.NewReadRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	draftRepo := repository.NewDraftRepository(db)
+	pollRepo := repository.NewPollRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	threadRepo := repository.NewThreadRepository(db)
+	voteRepo := repository.NewVoteRepository(db)
+	rankingRepo := repository.NewRankingRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	projectionRepo := repository.NewProjectionRepository(db)
+	avatarRepo := repository.NewAvatarRepository(db)
+
+	// База закрывается последней, после серверов, фоновых задач и шины
+	app := lifecycle.New(logger, cfg.HTTP.DrainDelay, cfg.HTTP.ShutdownTimeout)
+	app.OnClose("database", sqlDB.Close)
+	metrics.RegisterDB(sqlDB, "forum")
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "forum-service",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.OTLPEndpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logger.Fatalf("Tracing setup failed: %v", err)
+	}
+	// Спаны отправляются после остановки запросов и задач, но до закрытия базы
+	app.OnClose("tracing", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return shutdownTracing(ctx)
+	})
+
+	// Шина событий: через NATS приходят события auth-service, без них проекция
+	// пользователей пуста и авторизованные запросы не работают
+	bus, err := events.NewNATSBus(cfg.Events.NATSURL, "forum-service", cfg.Events.SubjectPrefix, logger)
+	if err != nil {
+		logger.Fatalf("NATS connection failed: %v", err)
+	}
+	app.OnClose("event bus", bus.Close)
+
+	if err := service.NewUserProjection(projectionRepo, logger).Subscribe(bus); err != nil {
+		logger.Fatalf("Event subscription failed: %v", err)
+	}
+	outboxRelay := service.NewOutboxRelay(outboxRepo, bus, logger)
+	app.Go("outbox relay", func(ctx context.Context) {
+		outboxRelay.Run(ctx, cfg.Events.OutboxInterval)
+	})
+
+	// События, пропущенные, пока форум был недоступен, восстанавливаются по снимкам
+	userSync := service.NewUserSync(projectionRepo, cfg.Events.SyncURL, cfg.Events.SyncToken, logger)
+	app.Go("user resync", func(ctx context.Context) {
+		userSync.Run(ctx, cfg.Events.ResyncInterval)
+	})
+
+	notifier, err := service.NewNotifier(notificationRepo, bus, logger)
+	if err != nil {
+		logger.Fatalf("Event subscription failed: %v", err)
+	}
+	mentionService := service.NewMentionService(userRepo, mentionRepo, notifier, logger)
+	activityService := service.NewSubscriptionService(subscriptionRepo, outboxRepo, map[string]service.ActivityDelivery{
+		entity.DeliveryInApp:       service.NewInAppDelivery(notifier),
+		entity.DeliveryEmailDigest: service.NewEmailDigestDelivery(digestRepo),
+	}, logger)
+	if err := activityService.Subscribe(bus); err != nil {
+		logger.Fatalf("Event subscription failed: %v", err)
+	}
+
+	digestSender := service.NewDigestSender(digestRepo, userRepo, service.NewLogMailer(logger), logger)
+	app.Go("digest sender", func(ctx context.Context) {
+		digestSender.Run(ctx, cfg.Jobs.DigestInterval)
+	})
+
+	webhookSender := service.NewWebhookSender(webhookRepo, logger)
+	app.Go("webhook sender", func(ctx context.Context) {
+		webhookSender.Run(ctx, cfg.Jobs.WebhookInterval)
+	})
 
 	// Инициализация контроллеров
-	postCtrl := controller.NewPostController(postRepo)
-	authCtrl := controller.NewAuthController(userRepo)
+	postCtrl := controller.NewPostController(
+		postRepo,
+		categoryRepo,
+		subscriptionRepo,
+		readRepo,
+		bookmarkRepo,
+		pollRepo,
+		tagRepo,
+		rankingRepo,
+		mentionService,
+		notifier,
+		activityService,
+		webhookSender,
+		logger,
+	)
+	commentCtrl := controller.NewCommentController(commentRepo, postRepo, notifier, activityService, webhookSender, logger)
+	notificationCtrl := controller.NewNotificationController(notificationRepo, notifier)
+	categoryCtrl := controller.NewCategoryController(categoryRepo, readRepo)
+	subscriptionCtrl := controller.NewSubscriptionController(subscriptionRepo, postRepo, categoryRepo)
+	readCtrl := controller.NewReadController(readRepo, postRepo, categoryRepo)
+	bookmarkCtrl := controller.NewBookmarkController(bookmarkRepo, postRepo)
+	draftCtrl := controller.NewDraftController(draftRepo)
+	pollCtrl := controller.NewPollController(pollRepo, postRepo)
+	tagCtrl := controller.NewTagController(tagRepo, readRepo, bookmarkRepo, mentionService)
+	threadCtrl := controller.NewThreadController(threadRepo, postRepo, categoryRepo, notifier, logger)
+	voteCtrl := controller.NewVoteController(voteRepo, postRepo, notifier, logger)
+
+	feedCtrl := controller.NewFeedController(postRepo, categoryRepo, userRepo, mentionService, strings.TrimRight(cfg.HTTP.SiteURL, "/"))
+	webhookCtrl := controller.NewWebhookController(webhookRepo, webhookSender)
+
+	// Просмотры постов копятся в памяти и записываются пачками
+	viewCounter := service.NewBatchedViewCounter(postRepo, cfg.Jobs.ViewDedupWindow, logger)
+	app.Go("view counter", func(ctx context.Context) {
+		viewCounter.Run(ctx, cfg.Jobs.ViewFlushInterval)
+	})
+
+	// Отложенные посты публикуются фоновым планировщиком
+	publishScheduler := service.NewPublishScheduler(postCtrl, logger)
+	app.Go("publish scheduler", func(ctx context.Context) {
+		publishScheduler.Run(ctx, cfg.Jobs.ScheduleInterval)
+	})
+
+	// Оценки для сортировки "hot" и трендов
+	rankingParams := service.DefaultRankingParams
+	rankingParams.Gravity = cfg.Jobs.HotGravity
+	rankingService := service.NewRankingService(rankingRepo, rankingParams, logger)
+	app.Go("ranking", func(ctx context.Context) {
+		rankingService.Run(ctx, cfg.Jobs.RankingInterval)
+	})
+
+	profileCtrl := controller.NewProfileController(
+		userRepo,
+		postRepo,
+		mentionService,
+		avatarRepo,
+		"/api/v1/avatars",
+	)
+
+	// Пробы готовности: трафик снимают только база и версия схемы. Очередь outbox
+	// и auth-service лишь отмечаются в отчете - без них форум продолжает отвечать.
+	probes := health.New(app.Ready, cfg.Health.CacheTTL, cfg.Health.CheckTimeout)
+	probes.Add("postgres", health.Ping(sqlDB))
+	probes.Add("migrations", func(ctx context.Context) error { return migrations.Verify(ctx, sqlDB) })
+	probes.AddOptional("outbox", outboxRelay.CheckBacklog(int64(cfg.Health.OutboxMaxPending)))
+	if cfg.Health.AuthServiceURL != "" {
+		authURL := strings.TrimRight(cfg.Health.AuthServiceURL, "/") + "/livez"
+		probes.AddOptional("auth-service", health.HTTP(http.DefaultClient, authURL))
+	}
 
 	// Middleware
-	authMiddleware := delivery.NewAuthMiddleware(logger, userRepo)
+	authMiddleware := delivery.NewAuthMiddleware(logger, auth.NewValidator(cfg.Auth.AccessSecret), userRepo, sanctionRepo, userSync)
+	writeLimiter := delivery.NewRateLimiter(cfg.RateLimit.PerMinute, cfg.RateLimit.Burst)
 	// Роутер
-	router := router.SetupRouter(postCtrl, authCtrl, authMiddleware)
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	router := router.SetupRouter(
+		postCtrl,
+		profileCtrl,
+		commentCtrl,
+		notificationCtrl,
+		categoryCtrl,
+		subscriptionCtrl,
+		readCtrl,
+		bookmarkCtrl,
+		draftCtrl,
+		pollCtrl,
+		tagCtrl,
+		threadCtrl,
+		voteCtrl,
+		feedCtrl,
+		webhookCtrl,
+		viewCounter,
+		authMiddleware,
+		writeLimiter,
+		cfg.CORS.AllowedOrigins,
+		probes,
+	)
+	// Без доверенных прокси адрес клиента для счетчика просмотров нельзя подделать X-Forwarded-For
+	if err := router.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		logger.Fatalf("Invalid trusted proxies: %v", err)
 	}
 
+	port := cfg.HTTP.Port
+
 	server := &http.Server{
 		Addr:         ":" + port,
 		Handler:      router,
@@ -111,17 +293,22 @@ func main() {
 	}
 
 	logger.Printf("Server starting on port %s", port)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	app.Serve(server)
+	if err := app.Run(); err != nil {
 		logger.Fatalf("Server failed: %v", err)
 	}
 }
 
-func autoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&entity.User{},
-		&entity.Post{},
-		&entity.ChatMessage{},
-		&entity.Token{},
-		&entity.EmailVerification{},
-	)
+// runCommand выполняет подкоманду вместо запуска сервера: forum-service migrate up
+func runCommand(db *sql.DB, args []string, logger *log.Logger) {
+	if args[0] != "migrate" {
+		logger.Fatalf("Unknown command %q, expected migrate", args[0])
+	}
+	m, err := migrations.New(db)
+	if err != nil {
+		logger.Fatalf("Failed to prepare migrations: %v", err)
+	}
+	if err := migrations.Command(m, args[1:], os.Stdout); err != nil {
+		logger.Fatal(err)
+	}
 }
@@ -0,0 +1,257 @@
+// Package config собирает настройки forum-service из значений по умолчанию,
+// файла, переменных окружения и флагов и проверяет их до запуска
+package config
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	HTTP       HTTP       `key:"http"`
+	DB         DB         `key:"db"`
+	Auth       Auth       `key:"auth"`
+	CORS       CORS       `key:"cors"`
+	RateLimit  RateLimit  `key:"rate_limit"`
+	Events     Events     `key:"events"`
+	Jobs       Jobs       `key:"jobs"`
+	Migrations Migrations `key:"migrations"`
+	Health     Health     `key:"health"`
+	Tracing    Tracing    `key:"tracing"`
+}
+
+type HTTP struct {
+	Port            string        `key:"port" env:"PORT" desc:"HTTP port"`
+	SiteURL         string        `key:"site_url" env:"SITE_URL" desc:"public site URL used in feeds"`
+	DrainDelay      time.Duration `key:"drain_delay" env:"DRAIN_DELAY" desc:"how long readiness reports false before the server stops accepting connections"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" desc:"on shutdown, deadline for in-flight requests and then, separately, for background jobs"`
+	// TrustedProxies - только этим адресам верим в X-Forwarded-For. Без них
+	// адрес клиента берется из соединения, и подделать его заголовком нельзя.
+	TrustedProxies []string `key:"trusted_proxies" env:"TRUSTED_PROXIES" desc:"comma-separated proxy IPs or CIDRs allowed to set X-Forwarded-For"`
+}
+
+type DB struct {
+	Host     string `key:"host" env:"DB_HOST" desc:"Postgres host"`
+	Port     string `key:"port" env:"DB_PORT" desc:"Postgres port"`
+	User     string `key:"user" env:"DB_USER" desc:"Postgres user"`
+	Password string `key:"password" env:"DB_PASSWORD" desc:"Postgres password"`
+	Name     string `key:"name" env:"DB_NAME" desc:"Postgres database"`
+	SSLMode  string `key:"sslmode" env:"DB_SSLMODE" desc:"Postgres sslmode"`
+}
+
+// Auth - форум только проверяет access-токены auth-service
+type Auth struct {
+	AccessSecret string `key:"access_secret" env:"ACCESS_TOKEN_SECRET" desc:"auth-service access token signing key"`
+}
+
+type CORS struct {
+	AllowedOrigins []string `key:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" desc:"comma-separated allowed origins"`
+}
+
+// RateLimit ограничивает запись (посты, комментарии, голоса) одного пользователя
+type RateLimit struct {
+	PerMinute int `key:"per_minute" env:"RATE_LIMIT_PER_MINUTE" desc:"write requests per minute per user, 0 disables"`
+	Burst     int `key:"burst" env:"RATE_LIMIT_BURST" desc:"write requests allowed in a burst"`
+}
+
+type Events struct {
+	NATSURL        string        `key:"nats_url" env:"NATS_URL" desc:"NATS server URL, required to receive auth-service events"`
+	SubjectPrefix  string        `key:"subject_prefix" env:"EVENTS_SUBJECT_PREFIX" desc:"prefix for event subjects"`
+	OutboxInterval time.Duration `key:"outbox_interval" env:"OUTBOX_INTERVAL" desc:"how often the outbox is relayed"`
+	// Пропущенные события восстанавливаются по снимкам пользователей из auth-service
+	SyncURL        string        `key:"sync_url" env:"EVENTS_SYNC_URL" desc:"auth-service base URL for user snapshots"`
+	SyncToken      string        `key:"sync_token" env:"EVENTS_SYNC_TOKEN" desc:"shared secret for the auth-service /internal API"`
+	ResyncInterval time.Duration `key:"resync_interval" env:"EVENTS_RESYNC_INTERVAL" desc:"how often all users are reconciled with auth-service"`
+}
+
+// Jobs - периоды фоновых задач
+type Jobs struct {
+	DigestInterval    time.Duration `key:"digest_interval" env:"DIGEST_INTERVAL" desc:"email digest period"`
+	WebhookInterval   time.Duration `key:"webhook_interval" env:"WEBHOOK_INTERVAL" desc:"webhook retry check period"`
+	ScheduleInterval  time.Duration `key:"schedule_interval" env:"SCHEDULE_INTERVAL" desc:"scheduled post check period"`
+	RankingInterval   time.Duration `key:"ranking_interval" env:"RANKING_INTERVAL" desc:"hot ranking refresh period"`
+	HotGravity        float64       `key:"hot_gravity" env:"HOT_GRAVITY" desc:"age penalty in the hot ranking"`
+	ViewDedupWindow   time.Duration `key:"view_dedup_window" env:"VIEW_DEDUP_WINDOW" desc:"window in which repeated views are not counted"`
+	ViewFlushInterval time.Duration `key:"view_flush_interval" env:"VIEW_FLUSH_INTERVAL" desc:"how often view counts are written"`
+}
+
+type Migrations struct {
+	Auto bool `key:"auto" env:"MIGRATE_ON_START" desc:"apply pending migrations on startup"`
+}
+
+// Health - пробы /readyz; результат кэшируется, чтобы частые пробы не нагружали базу
+type Health struct {
+	CacheTTL         time.Duration `key:"cache_ttl" env:"HEALTH_CACHE_TTL" desc:"how long a readiness report is reused"`
+	CheckTimeout     time.Duration `key:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" desc:"timeout of a single dependency check"`
+	OutboxMaxPending int           `key:"outbox_max_pending" env:"HEALTH_OUTBOX_MAX_PENDING" desc:"unpublished outbox events above which readiness reports degraded"`
+	AuthServiceURL   string        `key:"auth_service_url" env:"AUTH_SERVICE_URL" desc:"auth-service base URL reported in readiness without gating it, empty skips the check"`
+}
+
+// Tracing - экспорт спанов OpenTelemetry. Заголовки traceparent передаются
+// дальше при любом экспортере.
+type Tracing struct {
+	Exporter     string  `key:"exporter" env:"TRACING_EXPORTER" desc:"span exporter: none, stdout or otlp"`
+	OTLPEndpoint string  `key:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" desc:"OTLP/HTTP collector URL"`
+	SampleRatio  float64 `key:"sample_ratio" env:"TRACING_SAMPLE_RATIO" desc:"share of new traces that are recorded, from 0 to 1"`
+}
+
+// minSecretLength - короче ключ HMAC подбирается слишком легко
+const minSecretLength = 16
+
+func defaults() *Config {
+	return &Config{
+		HTTP: HTTP{
+			Port:            "8080",
+			SiteURL:         "http://localhost:3000",
+			DrainDelay:      5 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		DB: DB{
+			Host:     "localhost",
+			Port:     "5432",
+			User:     "user",
+			Password: "postgres",
+			Name:     "forum",
+			SSLMode:  "disable",
+		},
+		CORS:      CORS{AllowedOrigins: []string{"http://localhost:3000"}},
+		RateLimit: RateLimit{PerMinute: 30, Burst: 10},
+		Events: Events{
+			OutboxInterval: time.Second,
+			SyncURL:        "http://localhost:8081",
+			ResyncInterval: 10 * time.Minute,
+		},
+		Jobs: Jobs{
+			DigestInterval:    24 * time.Hour,
+			WebhookInterval:   15 * time.Second,
+			ScheduleInterval:  30 * time.Second,
+			RankingInterval:   5 * time.Minute,
+			HotGravity:        1.8,
+			ViewDedupWindow:   30 * time.Minute,
+			ViewFlushInterval: 10 * time.Second,
+		},
+		Migrations: Migrations{Auto: true},
+		Health: Health{
+			CacheTTL:         2 * time.Second,
+			CheckTimeout:     time.Second,
+			OutboxMaxPending: 1000,
+			AuthServiceURL:   "http://localhost:8081",
+		},
+		Tracing: Tracing{Exporter: "none", OTLPEndpoint: "http://localhost:4318", SampleRatio: 1},
+	}
+}
+
+// Load читает конфигурацию; args - аргументы командной строки без имени программы.
+// Вторым значением возвращает аргументы после флагов, например "migrate up".
+// При ошибках возвращает Problems со всеми найденными нарушениями.
+func Load(args []string) (*Config, []string, error) {
+	cfg := defaults()
+	rest, err := loadValidated(cfg, "forum-service", args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, rest, nil
+}
+
+func (c *Config) Validate() error {
+	var p Problems
+	p.check(validPort(c.HTTP.Port), "http.port: %q is not a valid port", c.HTTP.Port)
+	site, err := url.Parse(c.HTTP.SiteURL)
+	p.check(err == nil && (site.Scheme == "http" || site.Scheme == "https") && site.Host != "",
+		"http.site_url: %q is not an absolute http(s) URL", c.HTTP.SiteURL)
+	p.check(c.HTTP.DrainDelay >= 0, "http.drain_delay must not be negative")
+	p.check(c.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+	for _, proxy := range c.HTTP.TrustedProxies {
+		p.check(validProxy(proxy), "http.trusted_proxies: %q is not an IP address or CIDR", proxy)
+	}
+
+	p.check(c.DB.Host != "", "db.host is required")
+	p.check(validPort(c.DB.Port), "db.port: %q is not a valid port", c.DB.Port)
+	p.check(c.DB.User != "", "db.user is required")
+	p.check(c.DB.Name != "", "db.name is required")
+
+	p.check(len(c.Auth.AccessSecret) >= minSecretLength,
+		"auth.access_secret (ACCESS_TOKEN_SECRET) must be at least %d characters", minSecretLength)
+
+	p.check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins must list at least one origin")
+	for _, origin := range c.CORS.AllowedOrigins {
+		p.check(validOrigin(origin), "cors.allowed_origins: %q is not an origin like https://example.com", origin)
+	}
+
+	p.check(c.RateLimit.PerMinute >= 0, "rate_limit.per_minute must not be negative")
+	p.check(c.RateLimit.PerMinute == 0 || c.RateLimit.Burst > 0, "rate_limit.burst must be positive")
+
+	// Пользователи попадают в форум только событиями auth-service
+	if c.Events.NATSURL == "" {
+		p.check(false, "events.nats_url (NATS_URL) is required: users reach the forum only through auth-service events")
+	} else {
+		u, err := url.Parse(c.Events.NATSURL)
+		p.check(err == nil && u.Scheme == "nats" && u.Host != "", "events.nats_url: %q is not a nats:// URL", c.Events.NATSURL)
+	}
+	p.check(c.Events.OutboxInterval > 0, "events.outbox_interval must be positive")
+	syncURL, err := url.Parse(c.Events.SyncURL)
+	p.check(err == nil && (syncURL.Scheme == "http" || syncURL.Scheme == "https") && syncURL.Host != "",
+		"events.sync_url: %q is not an absolute http(s) URL", c.Events.SyncURL)
+	p.check(len(c.Events.SyncToken) >= minSecretLength,
+		"events.sync_token (EVENTS_SYNC_TOKEN) must be at least %d characters", minSecretLength)
+	p.check(c.Events.ResyncInterval > 0, "events.resync_interval must be positive")
+
+	p.check(c.Health.CacheTTL >= 0, "health.cache_ttl must not be negative")
+	p.check(c.Health.CheckTimeout > 0, "health.check_timeout must be positive")
+	p.check(c.Health.OutboxMaxPending > 0, "health.outbox_max_pending must be positive")
+	if c.Health.AuthServiceURL != "" {
+		u, err := url.Parse(c.Health.AuthServiceURL)
+		p.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"health.auth_service_url: %q is not an absolute http(s) URL", c.Health.AuthServiceURL)
+	}
+	c.Tracing.validate(&p)
+
+	for _, job := range []struct {
+		name   string
+		period time.Duration
+	}{
+		{"digest_interval", c.Jobs.DigestInterval},
+		{"webhook_interval", c.Jobs.WebhookInterval},
+		{"schedule_interval", c.Jobs.ScheduleInterval},
+		{"ranking_interval", c.Jobs.RankingInterval},
+		{"view_dedup_window", c.Jobs.ViewDedupWindow},
+		{"view_flush_interval", c.Jobs.ViewFlushInterval},
+	} {
+		p.check(job.period > 0, "jobs.%s must be positive", job.name)
+	}
+	p.check(c.Jobs.HotGravity > 0, "jobs.hot_gravity must be positive")
+	return p.err()
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+func validProxy(proxy string) bool {
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return true
+	}
+	return net.ParseIP(proxy) != nil
+}
+
+// validOrigin - "*" не допускается: CORS настроен с передачей учетных данных
+func validOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == ""
+}
+
+func (t Tracing) validate(p *Problems) {
+	switch t.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		u, err := url.Parse(t.OTLPEndpoint)
+		p.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"tracing.otlp_endpoint: %q is not an absolute http(s) URL", t.OTLPEndpoint)
+	default:
+		p.check(false, "tracing.exporter: %q is not one of none, stdout, otlp", t.Exporter)
+	}
+	p.check(t.SampleRatio >= 0 && t.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+}
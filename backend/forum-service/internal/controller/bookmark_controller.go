@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+type BookmarkController interface {
+	AddBookmark(userID, postID uint, req *entity.BookmarkRequest) (*entity.Bookmark, error)
+	RemoveBookmark(userID, postID uint) error
+	GetBookmarks(userID uint, folder string, page, limit int) ([]*entity.Bookmark, int64, error)
+}
+
+type bookmarkController struct {
+	bookmarks repository.BookmarkRepository
+	posts     repository.PostRepository
+}
+
+func NewBookmarkController(bookmarks repository.BookmarkRepository, posts repository.PostRepository) BookmarkController {
+	return &bookmarkController{bookmarks: bookmarks, posts: posts}
+}
+
+func (c *bookmarkController) AddBookmark(userID, postID uint, req *entity.BookmarkRequest) (*entity.Bookmark, error) {
+	if _, err := visiblePost(c.posts, postID, userID); err != nil {
+		return nil, err
+	}
+
+	bookmark := &entity.Bookmark{
+		UserID: userID,
+		PostID: postID,
+		Folder: strings.TrimSpace(req.Folder),
+		Note:   strings.TrimSpace(req.Note),
+	}
+	if err := c.bookmarks.Save(bookmark); err != nil {
+		return nil, err
+	}
+	return bookmark, nil
+}
+
+func (c *bookmarkController) RemoveBookmark(userID, postID uint) error {
+	return c.bookmarks.Delete(userID, postID)
+}
+
+func (c *bookmarkController) GetBookmarks(userID uint, folder string, page, limit int) ([]*entity.Bookmark, int64, error) {
+	folder = strings.TrimSpace(folder)
+	bookmarks, err := c.bookmarks.List(userID, folder, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := c.bookmarks.Count(userID, folder)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bookmarks, total, nil
+}
+
+// annotateBookmarks отмечает посты, сохраненные читателем viewerID
+func annotateBookmarks(bookmarks repository.BookmarkRepository, viewerID uint, posts []*entity.Post) error {
+	if viewerID == 0 || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	saved, err := bookmarks.BookmarkedPostIDs(viewerID, ids)
+	if err != nil {
+		return err
+	}
+	for _, p := range posts {
+		p.Bookmarked = saved[p.ID]
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+type CategoryController interface {
+	GetCategories(viewerID uint) ([]*entity.Category, error)
+	CreateCategory(req *entity.CategoryRequest) (*entity.Category, error)
+}
+
+type categoryController struct {
+	repo  repository.CategoryRepository
+	reads repository.ReadRepository
+}
+
+func NewCategoryController(repo repository.CategoryRepository, reads repository.ReadRepository) CategoryController {
+	return &categoryController{repo: repo, reads: reads}
+}
+
+func (c *categoryController) GetCategories(viewerID uint) ([]*entity.Category, error) {
+	categories, err := c.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if viewerID == 0 {
+		return categories, nil
+	}
+
+	unread, err := c.reads.CountUnreadByCategory(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		category.UnreadCount = unread[category.ID]
+	}
+	return categories, nil
+}
+
+func (c *categoryController) CreateCategory(req *entity.CategoryRequest) (*entity.Category, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !slugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("%w: slug may contain only lowercase letters, digits and dashes", ErrValidation)
+	}
+
+	category := &entity.Category{
+		Name:        strings.TrimSpace(req.Name),
+		Slug:        slug,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := c.repo.Create(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+)
+
+type CommentController interface {
+	GetComments(postID uint, page, limit int, viewerID uint) ([]*entity.Comment, error)
+	CreateComment(postID uint, req *entity.CommentRequest, authorID uint, authorRole string) (*entity.Comment, error)
+}
+
+type commentController struct {
+	comments repository.CommentRepository
+	posts    repository.PostRepository
+	notifier service.Notifier
+	activity service.SubscriptionService
+	webhooks service.WebhookDispatcher
+	logger   *log.Logger
+}
+
+func NewCommentController(
+	comments repository.CommentRepository,
+	posts repository.PostRepository,
+	notifier service.Notifier,
+	activity service.SubscriptionService,
+	webhooks service.WebhookDispatcher,
+	logger *log.Logger,
+) CommentController {
+	return &commentController{
+		comments: comments,
+		posts:    posts,
+		notifier: notifier,
+		activity: activity,
+		webhooks: webhooks,
+		logger:   logger,
+	}
+}
+
+func (c *commentController) GetComments(postID uint, page, limit int, viewerID uint) ([]*entity.Comment, error) {
+	if _, err := visiblePost(c.posts, postID, viewerID); err != nil {
+		return nil, err
+	}
+	comments, err := c.comments.GetByPost(postID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (c *commentController) CreateComment(postID uint, req *entity.CommentRequest, authorID uint, authorRole string) (*entity.Comment, error) {
+	post, err := visiblePost(c.posts, postID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Scheduled {
+		return nil, fmt.Errorf("%w: post is not published yet", ErrValidation)
+	}
+	// Модераторы могут отвечать в закрытой теме
+	if post.Locked && !entity.CanModerate(authorRole) {
+		return nil, ErrLocked
+	}
+
+	comment := &entity.Comment{
+		PostID:   postID,
+		AuthorID: authorID,
+		Content:  req.Content,
+	}
+	if err := c.comments.Create(comment); err != nil {
+		return nil, err
+	}
+
+	// Ошибка доставки уведомления не должна отменять создание комментария
+	if err := c.notifier.Notify(&entity.Notification{
+		UserID:    post.AuthorID,
+		ActorID:   &authorID,
+		Type:      entity.NotificationReply,
+		PostID:    &post.ID,
+		CommentID: &comment.ID,
+		Message:   fmt.Sprintf("New reply to your post %q", post.Title),
+	}); err != nil {
+		c.logger.Printf("Failed to notify about comment %d: %v", comment.ID, err)
+	}
+
+	c.activity.Publish(&entity.Activity{
+		Kind:            entity.ActivityCommentCreated,
+		ActorID:         authorID,
+		PostID:          post.ID,
+		CommentID:       &comment.ID,
+		CategoryID:      post.CategoryID,
+		Message:         fmt.Sprintf("New reply in %q", post.Title),
+		AlreadyNotified: []uint{post.AuthorID},
+	})
+	c.webhooks.Dispatch(entity.EventCommentCreated, comment)
+
+	return comment, nil
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+type DraftController interface {
+	CreateDraft(userID uint, req *entity.DraftRequest) (*entity.Draft, error)
+	SaveDraft(userID, id uint, req *entity.DraftRequest) (*entity.Draft, error)
+	GetDraft(userID, id uint) (*entity.Draft, error)
+	GetDrafts(userID uint, page, limit int) ([]*entity.Draft, error)
+	DeleteDraft(userID, id uint) error
+}
+
+type draftController struct {
+	drafts repository.DraftRepository
+}
+
+func NewDraftController(drafts repository.DraftRepository) DraftController {
+	return &draftController{drafts: drafts}
+}
+
+func (c *draftController) CreateDraft(userID uint, req *entity.DraftRequest) (*entity.Draft, error) {
+	draft := &entity.Draft{
+		UserID:        userID,
+		Title:         req.Title,
+		Content:       req.Content,
+		CategoryID:    req.CategoryID,
+		ReplyToPostID: req.ReplyToPostID,
+	}
+	if err := c.drafts.Create(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+func (c *draftController) SaveDraft(userID, id uint, req *entity.DraftRequest) (*entity.Draft, error) {
+	draft, err := c.drafts.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.Title = req.Title
+	draft.Content = req.Content
+	draft.CategoryID = req.CategoryID
+	draft.ReplyToPostID = req.ReplyToPostID
+	if err := c.drafts.Update(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+func (c *draftController) GetDraft(userID, id uint) (*entity.Draft, error) {
+	return c.drafts.GetByID(userID, id)
+}
+
+func (c *draftController) GetDrafts(userID uint, page, limit int) ([]*entity.Draft, error) {
+	return c.drafts.List(userID, (page-1)*limit, limit)
+}
+
+func (c *draftController) DeleteDraft(userID, id uint) error {
+	return c.drafts.Delete(userID, id)
+}
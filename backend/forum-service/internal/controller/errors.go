@@ -0,0 +1,12 @@
+package controller
+
+import "errors"
+
+var (
+	// ErrValidation оборачивает ошибки некорректного пользовательского ввода
+	ErrValidation = errors.New("validation error")
+	// ErrForbidden возвращается, когда у пользователя нет прав на действие
+	ErrForbidden = errors.New("forbidden")
+	// ErrLocked возвращается при попытке ответить или изменить закрытое обсуждение
+	ErrLocked = errors.New("thread is locked")
+)
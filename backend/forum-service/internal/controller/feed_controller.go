@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/feed"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+)
+
+// feedSize - сколько последних постов попадает в ленту
+const feedSize = 50
+
+// FeedController собирает ленты для RSS- и Atom-читалок. Ссылки ведут на сайт siteURL.
+type FeedController interface {
+	PostsFeed() (*feed.Feed, error)
+	CategoryFeed(slug string) (*feed.Feed, error)
+	AuthorFeed(username string) (*feed.Feed, error)
+}
+
+type feedController struct {
+	posts      repository.PostRepository
+	categories repository.CategoryRepository
+	users      repository.UserRepository
+	mentions   service.MentionService
+	siteURL    string
+	host       string
+}
+
+func NewFeedController(
+	posts repository.PostRepository,
+	categories repository.CategoryRepository,
+	users repository.UserRepository,
+	mentions service.MentionService,
+	siteURL string,
+) FeedController {
+	host := siteURL
+	if u, err := url.Parse(siteURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return &feedController{
+		posts:      posts,
+		categories: categories,
+		users:      users,
+		mentions:   mentions,
+		siteURL:    siteURL,
+		host:       host,
+	}
+}
+
+func (c *feedController) PostsFeed() (*feed.Feed, error) {
+	return c.build(nil, nil, "Latest posts", "New discussions on the forum", c.siteURL+"/")
+}
+
+func (c *feedController) CategoryFeed(slug string) (*feed.Feed, error) {
+	category, err := c.categories.GetBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(&category.ID, nil,
+		category.Name,
+		category.Description,
+		fmt.Sprintf("%s/categories/%s", c.siteURL, url.PathEscape(category.Slug)),
+	)
+}
+
+func (c *feedController) AuthorFeed(username string) (*feed.Feed, error) {
+	user, err := c.users.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(nil, &user.ID,
+		fmt.Sprintf("Posts by %s", user.Username),
+		user.Bio,
+		fmt.Sprintf("%s/users/%s", c.siteURL, url.PathEscape(user.Username)),
+	)
+}
+
+func (c *feedController) build(categoryID, authorID *uint, title, description, link string) (*feed.Feed, error) {
+	posts, err := c.posts.GetRecent(categoryID, authorID, feedSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.mentions.Render(posts...); err != nil {
+		return nil, err
+	}
+
+	// Пустая лента получает постоянную дату, чтобы ее ETag не менялся
+	f := &feed.Feed{
+		ID:          link,
+		Title:       title,
+		Description: description,
+		Link:        link,
+		Updated:     time.Unix(0, 0),
+	}
+	for _, post := range posts {
+		if post.UpdatedAt.After(f.Updated) {
+			f.Updated = post.UpdatedAt
+		}
+		f.Entries = append(f.Entries, c.entry(post))
+	}
+	return f, nil
+}
+
+func (c *feedController) entry(post *entity.Post) feed.Entry {
+	e := feed.Entry{
+		// Tag URI (RFC 4151) не меняется при смене адреса сайта или заголовка поста
+		ID:          fmt.Sprintf("tag:%s,%s:posts/%d", c.host, post.CreatedAt.UTC().Format("2006-01-02"), post.ID),
+		Title:       post.Title,
+		Link:        fmt.Sprintf("%s/posts/%d", c.siteURL, post.ID),
+		Author:      post.Author.Username,
+		ContentHTML: post.ContentHTML,
+		Published:   post.CreatedAt,
+		Updated:     post.UpdatedAt,
+	}
+	if post.Category != nil {
+		e.Category = post.Category.Name
+	}
+	return e
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+)
+
+type NotificationController interface {
+	List(userID uint, unreadOnly bool, page, limit int) ([]*entity.Notification, int64, error)
+	MarkRead(userID, id uint) error
+	MarkAllRead(userID uint) error
+	GetPreferences(userID uint) (map[string]bool, error)
+	UpdatePreferences(userID uint, prefs map[string]bool) (map[string]bool, error)
+	Subscribe(userID uint) (<-chan *entity.Notification, func())
+}
+
+type notificationController struct {
+	repo     repository.NotificationRepository
+	notifier service.Notifier
+}
+
+func NewNotificationController(repo repository.NotificationRepository, notifier service.Notifier) NotificationController {
+	return &notificationController{repo: repo, notifier: notifier}
+}
+
+func (c *notificationController) List(userID uint, unreadOnly bool, page, limit int) ([]*entity.Notification, int64, error) {
+	notifications, err := c.repo.List(userID, unreadOnly, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := c.repo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (c *notificationController) MarkRead(userID, id uint) error {
+	return c.repo.MarkRead(userID, id, time.Now())
+}
+
+func (c *notificationController) MarkAllRead(userID uint) error {
+	return c.repo.MarkAllRead(userID, time.Now())
+}
+
+func (c *notificationController) GetPreferences(userID uint) (map[string]bool, error) {
+	stored, err := c.repo.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := make(map[string]bool, len(entity.NotificationTypes))
+	for _, t := range entity.NotificationTypes {
+		prefs[t] = true
+	}
+	for _, p := range stored {
+		prefs[p.Type] = p.Enabled
+	}
+	return prefs, nil
+}
+
+func (c *notificationController) UpdatePreferences(userID uint, prefs map[string]bool) (map[string]bool, error) {
+	records := make([]*entity.NotificationPreference, 0, len(prefs))
+	for t, enabled := range prefs {
+		if !isNotificationType(t) {
+			return nil, fmt.Errorf("%w: unknown notification type %q", ErrValidation, t)
+		}
+		records = append(records, &entity.NotificationPreference{
+			UserID:  userID,
+			Type:    t,
+			Enabled: enabled,
+		})
+	}
+
+	if err := c.repo.SavePreferences(records); err != nil {
+		return nil, err
+	}
+	return c.GetPreferences(userID)
+}
+
+func (c *notificationController) Subscribe(userID uint) (<-chan *entity.Notification, func()) {
+	return c.notifier.Subscribe(userID)
+}
+
+func isNotificationType(t string) bool {
+	for _, known := range entity.NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
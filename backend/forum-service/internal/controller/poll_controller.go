@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrPollClosed возвращается при голосовании в завершенном опросе
+var ErrPollClosed = errors.New("poll is closed")
+
+type PollController interface {
+	CreatePoll(postID, authorID uint, req *entity.PollRequest) (*entity.PollResult, error)
+	Vote(pollID, userID uint, req *entity.VoteRequest) (*entity.PollResult, error)
+	ClosePoll(pollID, userID uint) (*entity.PollResult, error)
+	GetResults(pollID, viewerID uint) (*entity.PollResult, error)
+}
+
+type pollController struct {
+	polls repository.PollRepository
+	posts repository.PostRepository
+}
+
+func NewPollController(polls repository.PollRepository, posts repository.PostRepository) PollController {
+	return &pollController{polls: polls, posts: posts}
+}
+
+func (c *pollController) CreatePoll(postID, authorID uint, req *entity.PollRequest) (*entity.PollResult, error) {
+	post, err := c.posts.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != authorID {
+		return nil, fmt.Errorf("%w: only the author can add a poll", ErrForbidden)
+	}
+	if _, err := c.polls.GetByPostID(postID); err == nil {
+		return nil, fmt.Errorf("%w: post already has a poll", ErrValidation)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if req.ClosesAt != nil && !req.ClosesAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: closes_at must be in the future", ErrValidation)
+	}
+
+	poll := &entity.Poll{
+		PostID:    postID,
+		Question:  strings.TrimSpace(req.Question),
+		Multiple:  req.Multiple,
+		Anonymous: req.Anonymous,
+		ClosesAt:  req.ClosesAt,
+	}
+	seen := make(map[string]bool, len(req.Options))
+	for _, text := range req.Options {
+		text = strings.TrimSpace(text)
+		if text == "" || seen[strings.ToLower(text)] {
+			return nil, fmt.Errorf("%w: poll options must be non-empty and unique", ErrValidation)
+		}
+		seen[strings.ToLower(text)] = true
+		poll.Options = append(poll.Options, entity.PollOption{Text: text, Position: len(poll.Options)})
+	}
+
+	if err := c.polls.Create(poll); err != nil {
+		return nil, err
+	}
+	return buildPollResult(c.polls, poll, authorID)
+}
+
+func (c *pollController) Vote(pollID, userID uint, req *entity.VoteRequest) (*entity.PollResult, error) {
+	poll, err := c.polls.GetByID(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := visiblePost(c.posts, poll.PostID, userID); err != nil {
+		return nil, err
+	}
+	if poll.IsClosed(time.Now()) {
+		return nil, ErrPollClosed
+	}
+
+	valid := make(map[uint]bool, len(poll.Options))
+	for _, o := range poll.Options {
+		valid[o.ID] = true
+	}
+	chosen := make([]uint, 0, len(req.OptionIDs))
+	seen := make(map[uint]bool, len(req.OptionIDs))
+	for _, id := range req.OptionIDs {
+		if !valid[id] {
+			return nil, fmt.Errorf("%w: option %d does not belong to this poll", ErrValidation, id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			chosen = append(chosen, id)
+		}
+	}
+	if !poll.Multiple && len(chosen) > 1 {
+		return nil, fmt.Errorf("%w: this poll allows a single choice", ErrValidation)
+	}
+
+	if err := c.polls.Vote(pollID, userID, chosen); err != nil {
+		return nil, err
+	}
+	return buildPollResult(c.polls, poll, userID)
+}
+
+func (c *pollController) ClosePoll(pollID, userID uint) (*entity.PollResult, error) {
+	poll, err := c.polls.GetByID(pollID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := c.posts.GetByID(poll.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != userID {
+		return nil, fmt.Errorf("%w: only the author can close the poll", ErrForbidden)
+	}
+
+	now := time.Now()
+	if err := c.polls.Close(pollID, now); err != nil {
+		return nil, err
+	}
+	if !poll.IsClosed(now) {
+		poll.ClosesAt = &now
+	}
+	return buildPollResult(c.polls, poll, userID)
+}
+
+func (c *pollController) GetResults(pollID, viewerID uint) (*entity.PollResult, error) {
+	poll, err := c.polls.GetByID(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := visiblePost(c.posts, poll.PostID, viewerID); err != nil {
+		return nil, err
+	}
+	return buildPollResult(c.polls, poll, viewerID)
+}
+
+// pollForPost возвращает результаты опроса поста или nil, если опроса нет
+func pollForPost(polls repository.PollRepository, postID, viewerID uint) (*entity.PollResult, error) {
+	poll, err := polls.GetByPostID(postID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buildPollResult(polls, poll, viewerID)
+}
+
+func buildPollResult(polls repository.PollRepository, poll *entity.Poll, viewerID uint) (*entity.PollResult, error) {
+	counts, total, err := polls.CountVotes(poll.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var voters map[uint][]string
+	if !poll.Anonymous {
+		if voters, err = polls.GetVoters(poll.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &entity.PollResult{
+		ID:          poll.ID,
+		PostID:      poll.PostID,
+		Question:    poll.Question,
+		Multiple:    poll.Multiple,
+		Anonymous:   poll.Anonymous,
+		ClosesAt:    poll.ClosesAt,
+		Closed:      poll.IsClosed(time.Now()),
+		TotalVoters: total,
+	}
+	for _, o := range poll.Options {
+		result.Options = append(result.Options, &entity.PollOptionResult{
+			ID:     o.ID,
+			Text:   o.Text,
+			Votes:  counts[o.ID],
+			Voters: voters[o.ID],
+		})
+	}
+
+	if viewerID != 0 {
+		if result.MyVotes, err = polls.GetUserVotes(poll.ID, viewerID); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -1,60 +1,373 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"time"
 
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/metrics"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+	"gorm.io/gorm"
 )
 
 type PostController interface {
-	GetAllPosts() ([]*entity.Post, error)
-	GetPostByID(id uint) (*entity.Post, error)
+	GetAllPosts(categoryID *uint, sort string, viewerID uint) ([]*entity.Post, error)
+	// GetTrendingPosts возвращает посты с наибольшей активностью за последнюю неделю
+	GetTrendingPosts(page, limit int, viewerID uint) ([]*entity.Post, error)
+	GetPostByID(id uint, viewerID uint) (*entity.Post, error)
+	// CreatePost с PublishAt в будущем откладывает публикацию и ее побочные эффекты до PublishDue
 	CreatePost(req *entity.PostRequest, authorID uint) (*entity.Post, error)
-	UpdatePost(id uint, req *entity.PostRequest) (*entity.Post, error)
-	DeletePost(id uint) error
+	// UpdatePost и DeletePost разрешены автору, а с чужими постами - модераторам и администраторам
+	UpdatePost(id uint, req *entity.PostRequest, actorID uint, actorRole string) (*entity.Post, error)
+	DeletePost(id uint, actorID uint, actorRole string) error
+	// Действия модераторов
+	PinPost(id uint, scope string, actorID uint) (*entity.Post, error)
+	UnpinPost(id uint, actorID uint) (*entity.Post, error)
+	SetLocked(id uint, locked bool, actorID uint) (*entity.Post, error)
+	SetAnnouncement(id uint, announcement bool, actorID uint) (*entity.Post, error)
+	// PublishDue публикует отложенные посты, время которых наступило
+	PublishDue() (int, error)
 }
 
+// scheduledBatchSize - сколько отложенных постов публикуется за один проход
+const scheduledBatchSize = 100
+
 type postController struct {
-	repo repository.PostRepository
+	repo          repository.PostRepository
+	categories    repository.CategoryRepository
+	subscriptions repository.SubscriptionRepository
+	reads         repository.ReadRepository
+	bookmarks     repository.BookmarkRepository
+	polls         repository.PollRepository
+	tags          repository.TagRepository
+	ranks         repository.RankingRepository
+	mentions      service.MentionService
+	notifier      service.Notifier
+	activity      service.SubscriptionService
+	webhooks      service.WebhookDispatcher
+	logger        *log.Logger
 }
 
-func NewPostController(repo repository.PostRepository) PostController {
-	return &postController{repo: repo}
+func NewPostController(
+	repo repository.PostRepository,
+	categories repository.CategoryRepository,
+	subscriptions repository.SubscriptionRepository,
+	reads repository.ReadRepository,
+	bookmarks repository.BookmarkRepository,
+	polls repository.PollRepository,
+	tags repository.TagRepository,
+	ranks repository.RankingRepository,
+	mentions service.MentionService,
+	notifier service.Notifier,
+	activity service.SubscriptionService,
+	webhooks service.WebhookDispatcher,
+	logger *log.Logger,
+) PostController {
+	return &postController{
+		repo:          repo,
+		categories:    categories,
+		subscriptions: subscriptions,
+		reads:         reads,
+		bookmarks:     bookmarks,
+		polls:         polls,
+		tags:          tags,
+		ranks:         ranks,
+		mentions:      mentions,
+		notifier:      notifier,
+		activity:      activity,
+		webhooks:      webhooks,
+		logger:        logger,
+	}
 }
 
-func (c *postController) GetAllPosts() ([]*entity.Post, error) {
-	return c.repo.GetAll()
+func (c *postController) GetAllPosts(categoryID *uint, sort string, viewerID uint) ([]*entity.Post, error) {
+	switch sort {
+	case "":
+		sort = entity.SortNew
+	case entity.SortNew, entity.SortHot:
+	default:
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrValidation, sort)
+	}
+
+	posts, err := c.repo.GetAll(categoryID, sort, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return c.annotate(posts, viewerID)
 }
 
-func (c *postController) GetPostByID(id uint) (*entity.Post, error) {
-	return c.repo.GetByID(id) // Используем метод репозитория
+func (c *postController) GetTrendingPosts(page, limit int, viewerID uint) ([]*entity.Post, error) {
+	posts, err := c.ranks.GetTrending((page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.annotate(posts, viewerID)
+}
+
+// annotate дополняет посты ленты ссылками на упоминания и состоянием для читателя
+func (c *postController) annotate(posts []*entity.Post, viewerID uint) ([]*entity.Post, error) {
+	if err := c.mentions.Render(posts...); err != nil {
+		return nil, err
+	}
+	if err := annotateUnread(c.reads, viewerID, posts); err != nil {
+		return nil, err
+	}
+	if err := annotateBookmarks(c.bookmarks, viewerID, posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (c *postController) GetPostByID(id uint, viewerID uint) (*entity.Post, error) {
+	post, err := visiblePost(c.repo, id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.mentions.Render(post); err != nil {
+		return nil, err
+	}
+	if post.Poll, err = pollForPost(c.polls, post.ID, viewerID); err != nil {
+		return nil, err
+	}
+	if viewerID != 0 {
+		if err := annotateUnread(c.reads, viewerID, []*entity.Post{post}); err != nil {
+			return nil, err
+		}
+		if err := annotateBookmarks(c.bookmarks, viewerID, []*entity.Post{post}); err != nil {
+			return nil, err
+		}
+	}
+	return post, nil
 }
 
 func (c *postController) CreatePost(req *entity.PostRequest, authorID uint) (*entity.Post, error) {
+	if req.CategoryID != nil {
+		if _, err := c.categories.GetByID(*req.CategoryID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: unknown category %d", ErrValidation, *req.CategoryID)
+			}
+			return nil, err
+		}
+	}
+
+	tags, err := resolveTags(c.tags, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	post := &entity.Post{
-		Title:    req.Title,
-		Content:  req.Content,
-		AuthorID: authorID,
+		Title:      req.Title,
+		Content:    req.Content,
+		AuthorID:   authorID,
+		CategoryID: req.CategoryID,
+		Tags:       tags,
+	}
+	if req.PublishAt != nil && req.PublishAt.After(time.Now()) {
+		post.Scheduled = true
+		post.PublishAt = req.PublishAt
 	}
 
 	if err := c.repo.Create(post); err != nil {
 		return nil, err
 	}
+	metrics.PostsCreated.WithLabelValues(strconv.FormatBool(post.Scheduled)).Inc()
+
+	// Автор автоматически следит за своим обсуждением
+	if err := c.subscriptions.SubscribeIfAbsent(&entity.Subscription{
+		UserID:     authorID,
+		TargetType: entity.SubscriptionPost,
+		TargetID:   post.ID,
+		Delivery:   entity.DeliveryInApp,
+	}); err != nil {
+		c.logger.Printf("Failed to subscribe author to post %d: %v", post.ID, err)
+	}
+	if !post.Scheduled {
+		c.announce(post)
+	}
 
+	if err := c.mentions.Render(post); err != nil {
+		return nil, err
+	}
 	return post, nil
 }
 
 // post_controller.go
-func (c *postController) UpdatePost(id uint, req *entity.PostRequest) (*entity.Post, error) {
-	// Убираем лишнее получение поста, так как оно не используется
+func (c *postController) UpdatePost(id uint, req *entity.PostRequest, actorID uint, actorRole string) (*entity.Post, error) {
+	post, err := c.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizePostChange(post, actorID, actorRole); err != nil {
+		return nil, err
+	}
+	// Модераторы правят и закрытые темы
+	if post.Locked && !entity.CanModerate(actorRole) {
+		return nil, ErrLocked
+	}
+	var tags []entity.Tag
+	if req.Tags != nil {
+		if tags, err = resolveTags(c.tags, req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedPost, err := c.repo.Update(id, req)
 	if err != nil {
 		return nil, fmt.Errorf("update failed: %w", err)
 	}
+	if req.Tags != nil {
+		if err := c.tags.ReplacePostTags(updatedPost, tags); err != nil {
+			return nil, err
+		}
+	} else {
+		updatedPost.Tags = post.Tags
+	}
+	// Упомянутые в отложенном посте узнают о нем при публикации (announce)
+	if !updatedPost.Scheduled {
+		c.mentions.Process(updatedPost)
+	}
+	if err := c.mentions.Render(updatedPost); err != nil {
+		return nil, err
+	}
+	if entity.CanModerate(actorRole) {
+		c.notifyModeration(updatedPost, actorID, fmt.Sprintf("Your post %q was edited by a moderator", updatedPost.Title))
+	}
+	if !updatedPost.Scheduled {
+		c.webhooks.Dispatch(entity.EventPostUpdated, updatedPost)
+	}
 	return updatedPost, nil
 }
 
-func (c *postController) DeletePost(id uint) error {
-	return c.repo.Delete(id) // Используем метод репозитория
+func (c *postController) DeletePost(id uint, actorID uint, actorRole string) error {
+	post, err := c.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := authorizePostChange(post, actorID, actorRole); err != nil {
+		return err
+	}
+	if err := c.repo.Delete(id); err != nil {
+		return err
+	}
+	if entity.CanModerate(actorRole) {
+		c.notifyModeration(post, actorID, fmt.Sprintf("Your post %q was removed by a moderator", post.Title))
+	}
+	if !post.Scheduled {
+		c.webhooks.Dispatch(entity.EventPostDeleted, post)
+	}
+	return nil
+}
+
+// authorizePostChange пропускает автора поста, модераторов и администраторов
+func authorizePostChange(post *entity.Post, actorID uint, actorRole string) error {
+	if post.AuthorID == actorID || entity.CanModerate(actorRole) {
+		return nil
+	}
+	return fmt.Errorf("%w: only the author or a moderator can change this post", ErrForbidden)
+}
+
+func (c *postController) PinPost(id uint, scope string, actorID uint) (*entity.Post, error) {
+	post, err := c.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	switch scope {
+	case entity.PinGlobal:
+	case entity.PinCategory:
+		if post.CategoryID == nil {
+			return nil, fmt.Errorf("%w: post %d has no category", ErrValidation, id)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown pin scope %q", ErrValidation, scope)
+	}
+	return c.repo.UpdateFlags(id, map[string]interface{}{
+		"Pin":      scope,
+		"PinnedAt": time.Now(),
+	})
+}
+
+func (c *postController) UnpinPost(id uint, actorID uint) (*entity.Post, error) {
+	return c.repo.UpdateFlags(id, map[string]interface{}{
+		"Pin":      entity.PinNone,
+		"PinnedAt": nil,
+	})
+}
+
+func (c *postController) SetLocked(id uint, locked bool, actorID uint) (*entity.Post, error) {
+	post, err := c.repo.UpdateFlags(id, map[string]interface{}{"Locked": locked})
+	if err != nil {
+		return nil, err
+	}
+	if locked {
+		c.notifyModeration(post, actorID, fmt.Sprintf("Your post %q was locked by a moderator", post.Title))
+	}
+	return post, nil
+}
+
+func (c *postController) SetAnnouncement(id uint, announcement bool, actorID uint) (*entity.Post, error) {
+	return c.repo.UpdateFlags(id, map[string]interface{}{"Announcement": announcement})
+}
+
+func (c *postController) PublishDue() (int, error) {
+	published := 0
+	for {
+		posts, err := c.repo.ClaimDue(time.Now(), scheduledBatchSize)
+		if err != nil {
+			return published, err
+		}
+		for _, post := range posts {
+			c.announce(post)
+		}
+		published += len(posts)
+		if len(posts) < scheduledBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// announce рассылает уведомления о появлении поста: подписчикам категории, упомянутым и вебхукам
+func (c *postController) announce(post *entity.Post) {
+	if post.CategoryID != nil {
+		c.activity.Publish(&entity.Activity{
+			Kind:       entity.ActivityPostCreated,
+			ActorID:    post.AuthorID,
+			PostID:     post.ID,
+			CategoryID: post.CategoryID,
+			Message:    fmt.Sprintf("New post %q in a category you follow", post.Title),
+		})
+	}
+	c.mentions.Process(post)
+	c.webhooks.Dispatch(entity.EventPostCreated, post)
+}
+
+// notifyModeration уведомляет автора, если его пост изменил кто-то другой
+func (c *postController) notifyModeration(post *entity.Post, actorID uint, message string) {
+	if post.AuthorID == actorID {
+		return
+	}
+	if err := c.notifier.Notify(&entity.Notification{
+		UserID:  post.AuthorID,
+		ActorID: &actorID,
+		Type:    entity.NotificationModeration,
+		PostID:  &post.ID,
+		Message: message,
+	}); err != nil {
+		c.logger.Printf("Failed to notify about moderation of post %d: %v", post.ID, err)
+	}
+}
+
+// visiblePost загружает пост, скрывая отложенный от всех, кроме автора
+func visiblePost(posts repository.PostRepository, id, viewerID uint) (*entity.Post, error) {
+	post, err := posts.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Scheduled && post.AuthorID != viewerID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return post, nil
 }
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+)
+
+// MaxAvatarSize - максимальный размер загружаемого аватара
+const MaxAvatarSize = 2 << 20
+
+var avatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type ProfileController interface {
+	GetProfile(username string) (*entity.Profile, error)
+	GetOwnProfile(userID uint) (*entity.Profile, error)
+	GetUserPosts(username string, page, limit int) ([]*entity.Post, error)
+	UpdateProfile(userID uint, req *entity.ProfileUpdateRequest) (*entity.Profile, error)
+	UpdateAvatar(userID uint, file *multipart.FileHeader) (*entity.Profile, error)
+	GetAvatar(userID uint) (*entity.Avatar, error)
+}
+
+type profileController struct {
+	users     repository.UserRepository
+	posts     repository.PostRepository
+	mentions  service.MentionService
+	avatars   repository.AvatarRepository
+	avatarURL string
+}
+
+// NewProfileController создает контроллер профилей. Аватары хранятся в базе,
+// чтобы их видели все реплики, и отдаются по адресу avatarURL/<id пользователя>.
+func NewProfileController(
+	users repository.UserRepository,
+	posts repository.PostRepository,
+	mentions service.MentionService,
+	avatars repository.AvatarRepository,
+	avatarURL string,
+) ProfileController {
+	return &profileController{
+		users:     users,
+		posts:     posts,
+		mentions:  mentions,
+		avatars:   avatars,
+		avatarURL: strings.TrimSuffix(avatarURL, "/"),
+	}
+}
+
+func (c *profileController) GetProfile(username string) (*entity.Profile, error) {
+	user, err := c.users.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return c.buildProfile(user)
+}
+
+func (c *profileController) GetOwnProfile(userID uint) (*entity.Profile, error) {
+	user, err := c.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return c.buildProfile(user)
+}
+
+func (c *profileController) GetUserPosts(username string, page, limit int) ([]*entity.Post, error) {
+	user, err := c.users.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := c.posts.GetByAuthor(user.ID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.mentions.Render(posts...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (c *profileController) UpdateProfile(userID uint, req *entity.ProfileUpdateRequest) (*entity.Profile, error) {
+	updates := map[string]interface{}{}
+	if req.DisplayName != nil {
+		updates["display_name"] = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Bio != nil {
+		updates["bio"] = strings.TrimSpace(*req.Bio)
+	}
+	if req.Location != nil {
+		updates["location"] = strings.TrimSpace(*req.Location)
+	}
+	if req.Website != nil {
+		website := strings.TrimSpace(*req.Website)
+		if website != "" {
+			u, err := url.Parse(website)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("%w: website must be an http(s) URL", ErrValidation)
+			}
+		}
+		updates["website"] = website
+	}
+	if req.Signature != nil {
+		updates["signature"] = strings.TrimSpace(*req.Signature)
+	}
+
+	user, err := c.users.UpdateProfile(userID, updates)
+	if err != nil {
+		return nil, err
+	}
+	return c.buildProfile(user)
+}
+
+func (c *profileController) UpdateAvatar(userID uint, file *multipart.FileHeader) (*entity.Profile, error) {
+	if file.Size > MaxAvatarSize {
+		return nil, fmt.Errorf("%w: avatar must not exceed %d bytes", ErrValidation, MaxAvatarSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, MaxAvatarSize))
+	if err != nil {
+		return nil, err
+	}
+	// Тип определяем по содержимому, а не по имени файла
+	contentType := http.DetectContentType(data)
+	if !avatarTypes[contentType] {
+		return nil, fmt.Errorf("%w: avatar must be a JPEG, PNG, GIF or WebP image", ErrValidation)
+	}
+
+	// Новый аватар заменяет прежний, ничего старого не остается
+	now := time.Now()
+	avatar := &entity.Avatar{UserID: userID, ContentType: contentType, Data: data, UpdatedAt: now}
+	if err := c.avatars.Save(avatar); err != nil {
+		return nil, err
+	}
+
+	// Версия в адресе не дает браузерам показывать закэшированный прежний аватар
+	user, err := c.users.UpdateProfile(userID, map[string]interface{}{
+		"avatar_url": fmt.Sprintf("%s/%d?v=%d", c.avatarURL, userID, now.Unix()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.buildProfile(user)
+}
+
+func (c *profileController) GetAvatar(userID uint) (*entity.Avatar, error) {
+	return c.avatars.Get(userID)
+}
+
+func (c *profileController) buildProfile(user *entity.User) (*entity.Profile, error) {
+	count, err := c.posts.CountByAuthor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := user.DisplayName
+	if displayName == "" {
+		displayName = user.Username
+	}
+
+	return &entity.Profile{
+		ID:          user.ID,
+		Username:    user.Username,
+		DisplayName: displayName,
+		Bio:         user.Bio,
+		AvatarURL:   user.AvatarURL,
+		Location:    user.Location,
+		Website:     user.Website,
+		Signature:   user.Signature,
+		PostCount:   count,
+		JoinedAt:    user.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+type ReadController interface {
+	MarkPostRead(userID, postID, lastCommentID uint) error
+	MarkCategoryRead(userID, categoryID uint) error
+	FirstUnread(userID, postID uint, limit int) (*entity.FirstUnread, error)
+}
+
+type readController struct {
+	reads      repository.ReadRepository
+	posts      repository.PostRepository
+	categories repository.CategoryRepository
+}
+
+func NewReadController(
+	reads repository.ReadRepository,
+	posts repository.PostRepository,
+	categories repository.CategoryRepository,
+) ReadController {
+	return &readController{reads: reads, posts: posts, categories: categories}
+}
+
+func (c *readController) MarkPostRead(userID, postID, lastCommentID uint) error {
+	if _, err := visiblePost(c.posts, postID, userID); err != nil {
+		return err
+	}
+	return c.reads.MarkPostRead(userID, postID, lastCommentID, time.Now())
+}
+
+func (c *readController) MarkCategoryRead(userID, categoryID uint) error {
+	if _, err := c.categories.GetByID(categoryID); err != nil {
+		return err
+	}
+	return c.reads.MarkCategoryRead(userID, categoryID, time.Now())
+}
+
+func (c *readController) FirstUnread(userID, postID uint, limit int) (*entity.FirstUnread, error) {
+	if _, err := visiblePost(c.posts, postID, userID); err != nil {
+		return nil, err
+	}
+
+	commentID, before, err := c.reads.FirstUnreadComment(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Без непрочитанного отправляем на последнюю страницу
+	position := before
+	if commentID == nil && before > 0 {
+		position = before - 1
+	}
+	return &entity.FirstUnread{
+		CommentID: commentID,
+		Page:      int(position)/limit + 1,
+	}, nil
+}
+
+// annotateUnread заполняет признаки непрочитанного у постов для читателя viewerID
+func annotateUnread(reads repository.ReadRepository, viewerID uint, posts []*entity.Post) error {
+	if viewerID == 0 || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	state, err := reads.GetUnread(viewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		if s, ok := state[p.ID]; ok {
+			p.UnreadComments = s.UnreadComments
+			p.Unread = s.Unseen || s.UnreadComments > 0
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+type SubscriptionController interface {
+	Subscribe(userID uint, targetType string, targetID uint, delivery string) (*entity.Subscription, error)
+	Unsubscribe(userID uint, targetType string, targetID uint) error
+	GetWatched(userID uint, targetType string, page, limit int) ([]*entity.Subscription, error)
+}
+
+type subscriptionController struct {
+	subs       repository.SubscriptionRepository
+	posts      repository.PostRepository
+	categories repository.CategoryRepository
+}
+
+func NewSubscriptionController(
+	subs repository.SubscriptionRepository,
+	posts repository.PostRepository,
+	categories repository.CategoryRepository,
+) SubscriptionController {
+	return &subscriptionController{subs: subs, posts: posts, categories: categories}
+}
+
+func (c *subscriptionController) Subscribe(userID uint, targetType string, targetID uint, delivery string) (*entity.Subscription, error) {
+	if delivery == "" {
+		delivery = entity.DeliveryInApp
+	}
+	if delivery != entity.DeliveryInApp && delivery != entity.DeliveryEmailDigest {
+		return nil, fmt.Errorf("%w: unknown delivery %q", ErrValidation, delivery)
+	}
+
+	var err error
+	switch targetType {
+	case entity.SubscriptionPost:
+		_, err = visiblePost(c.posts, targetID, userID)
+	case entity.SubscriptionCategory:
+		_, err = c.categories.GetByID(targetID)
+	default:
+		return nil, fmt.Errorf("%w: unknown subscription target %q", ErrValidation, targetType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &entity.Subscription{
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Delivery:   delivery,
+	}
+	if err := c.subs.Subscribe(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (c *subscriptionController) Unsubscribe(userID uint, targetType string, targetID uint) error {
+	return c.subs.Unsubscribe(userID, targetType, targetID)
+}
+
+func (c *subscriptionController) GetWatched(userID uint, targetType string, page, limit int) ([]*entity.Subscription, error) {
+	subs, err := c.subs.ListByUser(userID, targetType, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var postIDs, categoryIDs []uint
+	for _, sub := range subs {
+		switch sub.TargetType {
+		case entity.SubscriptionPost:
+			postIDs = append(postIDs, sub.TargetID)
+		case entity.SubscriptionCategory:
+			categoryIDs = append(categoryIDs, sub.TargetID)
+		}
+	}
+
+	posts, err := c.posts.GetByIDs(postIDs)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := c.categories.GetByIDs(categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := map[string]map[uint]string{
+		entity.SubscriptionPost:     {},
+		entity.SubscriptionCategory: {},
+	}
+	for _, p := range posts {
+		titles[entity.SubscriptionPost][p.ID] = p.Title
+	}
+	for _, cat := range categories {
+		titles[entity.SubscriptionCategory][cat.ID] = cat.Name
+	}
+	for _, sub := range subs {
+		sub.Title = titles[sub.TargetType][sub.TargetID]
+	}
+	return subs, nil
+}
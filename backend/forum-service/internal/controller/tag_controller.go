@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/tag"
+)
+
+type TagController interface {
+	// SearchTags подсказывает теги по началу названия, пустой префикс дает самые популярные
+	SearchTags(prefix string, limit int) ([]*entity.Tag, error)
+	GetTagPosts(name string, page, limit int, viewerID uint) (*entity.Tag, []*entity.Post, error)
+	CurateTag(name string) (*entity.Tag, error)
+	RenameTag(name, newName string) (*entity.Tag, error)
+	MergeTags(name, into string) (*entity.Tag, error)
+}
+
+type tagController struct {
+	tags      repository.TagRepository
+	reads     repository.ReadRepository
+	bookmarks repository.BookmarkRepository
+	mentions  service.MentionService
+}
+
+func NewTagController(
+	tags repository.TagRepository,
+	reads repository.ReadRepository,
+	bookmarks repository.BookmarkRepository,
+	mentions service.MentionService,
+) TagController {
+	return &tagController{tags: tags, reads: reads, bookmarks: bookmarks, mentions: mentions}
+}
+
+func (c *tagController) SearchTags(prefix string, limit int) ([]*entity.Tag, error) {
+	// Префикс может быть недописанным тегом, поэтому не проверяется целиком
+	prefix = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(prefix), "#"))
+	prefix = strings.Join(strings.Fields(prefix), "-")
+	return c.tags.Search(prefix, limit)
+}
+
+func (c *tagController) GetTagPosts(name string, page, limit int, viewerID uint) (*entity.Tag, []*entity.Post, error) {
+	t, err := c.lookup(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.PostCount, err = c.tags.CountPosts(t.ID); err != nil {
+		return nil, nil, err
+	}
+
+	posts, err := c.tags.GetPosts(t.ID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.mentions.Render(posts...); err != nil {
+		return nil, nil, err
+	}
+	if err := annotateUnread(c.reads, viewerID, posts); err != nil {
+		return nil, nil, err
+	}
+	if err := annotateBookmarks(c.bookmarks, viewerID, posts); err != nil {
+		return nil, nil, err
+	}
+	return t, posts, nil
+}
+
+func (c *tagController) CurateTag(name string) (*entity.Tag, error) {
+	normalized, err := normalizeTag(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.tags.Curate(normalized)
+}
+
+func (c *tagController) RenameTag(name, newName string) (*entity.Tag, error) {
+	t, err := c.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeTag(newName)
+	if err != nil {
+		return nil, err
+	}
+	if normalized == t.Name {
+		return t, nil
+	}
+
+	// Занятое имя дает gorm.ErrDuplicatedKey: такие теги нужно объединять
+	if err := c.tags.Rename(t.ID, normalized); err != nil {
+		return nil, err
+	}
+	t.Name = normalized
+	return t, nil
+}
+
+func (c *tagController) MergeTags(name, into string) (*entity.Tag, error) {
+	source, err := c.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.lookup(into)
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == target.ID {
+		return nil, fmt.Errorf("%w: cannot merge a tag into itself", ErrValidation)
+	}
+
+	if err := c.tags.Merge(source.ID, target.ID); err != nil {
+		return nil, err
+	}
+	if target.PostCount, err = c.tags.CountPosts(target.ID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (c *tagController) lookup(name string) (*entity.Tag, error) {
+	normalized, err := normalizeTag(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.tags.GetByName(normalized)
+}
+
+func normalizeTag(name string) (string, error) {
+	normalized, err := tag.Normalize(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	return normalized, nil
+}
+
+// resolveTags проверяет теги из запроса и возвращает их записи, создавая новые
+func resolveTags(tags repository.TagRepository, names []string) ([]entity.Tag, error) {
+	normalized, err := tag.NormalizeAll(names)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	return tags.Resolve(normalized)
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+	"gorm.io/gorm"
+)
+
+// ThreadController - инструменты модераторов для наведения порядка в обсуждениях
+type ThreadController interface {
+	MovePost(postID uint, req *entity.MoveRequest, actorID uint) (*entity.Post, error)
+	MergePosts(sourceID uint, req *entity.MergeRequest, actorID uint) (*entity.Post, error)
+	SplitPost(sourceID uint, req *entity.SplitRequest, actorID uint) (*entity.Post, error)
+	// ResolveRedirect возвращает пост, в который был влит пост с указанным ID
+	ResolveRedirect(postID uint) (uint, error)
+	GetModerationLog(page, limit int) ([]*entity.ModerationLog, error)
+}
+
+type threadController struct {
+	threads    repository.ThreadRepository
+	posts      repository.PostRepository
+	categories repository.CategoryRepository
+	notifier   service.Notifier
+	logger     *log.Logger
+}
+
+func NewThreadController(
+	threads repository.ThreadRepository,
+	posts repository.PostRepository,
+	categories repository.CategoryRepository,
+	notifier service.Notifier,
+	logger *log.Logger,
+) ThreadController {
+	return &threadController{
+		threads:    threads,
+		posts:      posts,
+		categories: categories,
+		notifier:   notifier,
+		logger:     logger,
+	}
+}
+
+func (c *threadController) MovePost(postID uint, req *entity.MoveRequest, actorID uint) (*entity.Post, error) {
+	post, err := c.posts.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkCategory(&req.CategoryID); err != nil {
+		return nil, err
+	}
+	if post.CategoryID != nil && *post.CategoryID == req.CategoryID {
+		return post, nil
+	}
+
+	from := "none"
+	if post.CategoryID != nil {
+		from = fmt.Sprint(*post.CategoryID)
+	}
+	categoryID := req.CategoryID
+	if err := c.threads.Move(postID, categoryID, &entity.ModerationLog{
+		ActorID:  actorID,
+		Action:   entity.ModerationMove,
+		PostID:   postID,
+		TargetID: &categoryID,
+		Details:  fmt.Sprintf("category %s -> %d", from, categoryID),
+		Reason:   req.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	c.notifyAuthor(post, actorID, fmt.Sprintf("Your post %q was moved to another category by a moderator", post.Title))
+	return c.posts.GetByID(postID)
+}
+
+func (c *threadController) MergePosts(sourceID uint, req *entity.MergeRequest, actorID uint) (*entity.Post, error) {
+	if sourceID == req.TargetPostID {
+		return nil, fmt.Errorf("%w: cannot merge a post into itself", ErrValidation)
+	}
+	source, err := c.posts.GetByID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.posts.GetByID(req.TargetPostID)
+	if err != nil {
+		return nil, err
+	}
+	if source.Scheduled || target.Scheduled {
+		return nil, fmt.Errorf("%w: scheduled posts cannot be merged", ErrValidation)
+	}
+
+	targetID := target.ID
+	if err := c.threads.Merge(source.ID, target.ID, &entity.ModerationLog{
+		ActorID:  actorID,
+		Action:   entity.ModerationMerge,
+		PostID:   source.ID,
+		TargetID: &targetID,
+		Details:  fmt.Sprintf("post %d merged into %d", source.ID, target.ID),
+		Reason:   req.Reason,
+	}); err != nil {
+		if errors.Is(err, repository.ErrPollConflict) {
+			return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+		}
+		return nil, err
+	}
+
+	c.notifyAuthor(source, actorID, fmt.Sprintf("Your post %q was merged into %q by a moderator", source.Title, target.Title))
+	return c.posts.GetByID(target.ID)
+}
+
+func (c *threadController) SplitPost(sourceID uint, req *entity.SplitRequest, actorID uint) (*entity.Post, error) {
+	source, err := c.posts.GetByID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID := source.CategoryID
+	if req.CategoryID != nil {
+		if err := c.checkCategory(req.CategoryID); err != nil {
+			return nil, err
+		}
+		categoryID = req.CategoryID
+	}
+
+	ids := uniqueIDs(req.CommentIDs)
+	idList := make([]string, 0, len(ids))
+	for _, id := range ids {
+		idList = append(idList, fmt.Sprint(id))
+	}
+
+	post := &entity.Post{
+		Title:      strings.TrimSpace(req.Title),
+		CategoryID: categoryID,
+	}
+	if err := c.threads.Split(source.ID, ids, post, &entity.ModerationLog{
+		ActorID: actorID,
+		Action:  entity.ModerationSplit,
+		PostID:  source.ID,
+		Details: "comments " + strings.Join(idList, ","),
+		Reason:  req.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	c.notifyAuthor(post, actorID, fmt.Sprintf("Your reply in %q was moved to a new thread %q", source.Title, post.Title))
+	return c.posts.GetByID(post.ID)
+}
+
+func (c *threadController) ResolveRedirect(postID uint) (uint, error) {
+	return c.threads.GetRedirect(postID)
+}
+
+func (c *threadController) GetModerationLog(page, limit int) ([]*entity.ModerationLog, error) {
+	return c.threads.GetLog((page-1)*limit, limit)
+}
+
+func (c *threadController) checkCategory(id *uint) error {
+	if _, err := c.categories.GetByID(*id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: unknown category %d", ErrValidation, *id)
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *threadController) notifyAuthor(post *entity.Post, actorID uint, message string) {
+	if err := c.notifier.Notify(&entity.Notification{
+		UserID:  post.AuthorID,
+		ActorID: &actorID,
+		Type:    entity.NotificationModeration,
+		PostID:  &post.ID,
+		Message: message,
+	}); err != nil {
+		c.logger.Printf("Failed to notify about moderation of post %d: %v", post.ID, err)
+	}
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+	"gorm.io/gorm"
+)
+
+type VoteController interface {
+	// Vote ставит или меняет голос и возвращает новый счет поста
+	Vote(postID, userID uint, value int) (int64, error)
+	Unvote(postID, userID uint) (int64, error)
+}
+
+type voteController struct {
+	votes    repository.VoteRepository
+	posts    repository.PostRepository
+	notifier service.Notifier
+	logger   *log.Logger
+}
+
+func NewVoteController(
+	votes repository.VoteRepository,
+	posts repository.PostRepository,
+	notifier service.Notifier,
+	logger *log.Logger,
+) VoteController {
+	return &voteController{votes: votes, posts: posts, notifier: notifier, logger: logger}
+}
+
+func (c *voteController) Vote(postID, userID uint, value int) (int64, error) {
+	if value != 1 && value != -1 {
+		return 0, fmt.Errorf("%w: vote must be 1 or -1", ErrValidation)
+	}
+	post, err := c.posts.GetByID(postID)
+	if err != nil {
+		return 0, err
+	}
+	if post.Scheduled {
+		return 0, gorm.ErrRecordNotFound
+	}
+
+	score, created, err := c.votes.Vote(postID, userID, value, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	// Автор узнает только о первом положительном голосе пользователя
+	if created && value > 0 {
+		if err := c.notifier.Notify(&entity.Notification{
+			UserID:  post.AuthorID,
+			ActorID: &userID,
+			Type:    entity.NotificationReaction,
+			PostID:  &post.ID,
+			Message: fmt.Sprintf("Someone upvoted your post %q", post.Title),
+		}); err != nil {
+			c.logger.Printf("Failed to notify about vote on post %d: %v", post.ID, err)
+		}
+	}
+	return score, nil
+}
+
+func (c *voteController) Unvote(postID, userID uint) (int64, error) {
+	if _, err := c.posts.GetByID(postID); err != nil {
+		return 0, err
+	}
+	return c.votes.Unvote(postID, userID)
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+)
+
+type WebhookController interface {
+	CreateWebhook(req *entity.WebhookRequest, adminID uint) (*entity.WebhookWithSecret, error)
+	UpdateWebhook(id uint, req *entity.WebhookRequest) (*entity.Webhook, error)
+	DeleteWebhook(id uint) error
+	GetWebhooks() ([]*entity.Webhook, error)
+	GetDeliveries(webhookID uint, page, limit int) ([]*entity.WebhookDelivery, error)
+	// Redeliver ставит доставку в очередь заново, сбрасывая счетчик попыток
+	Redeliver(webhookID, deliveryID uint) (*entity.WebhookDelivery, error)
+}
+
+type webhookController struct {
+	repo   repository.WebhookRepository
+	sender *service.WebhookSender
+}
+
+func NewWebhookController(repo repository.WebhookRepository, sender *service.WebhookSender) WebhookController {
+	return &webhookController{repo: repo, sender: sender}
+}
+
+func (c *webhookController) CreateWebhook(req *entity.WebhookRequest, adminID uint) (*entity.WebhookWithSecret, error) {
+	if err := validateWebhook(req); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	webhook := &entity.Webhook{
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    req.Events,
+		Active:    req.Active == nil || *req.Active,
+		CreatedBy: adminID,
+	}
+	if err := c.repo.Create(webhook); err != nil {
+		return nil, err
+	}
+	return &entity.WebhookWithSecret{Webhook: webhook, Secret: secret}, nil
+}
+
+func (c *webhookController) UpdateWebhook(id uint, req *entity.WebhookRequest) (*entity.Webhook, error) {
+	if err := validateWebhook(req); err != nil {
+		return nil, err
+	}
+	webhook, err := c.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.URL = req.URL
+	webhook.Events = req.Events
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	if err := c.repo.Update(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (c *webhookController) DeleteWebhook(id uint) error {
+	return c.repo.Delete(id)
+}
+
+func (c *webhookController) GetWebhooks() ([]*entity.Webhook, error) {
+	return c.repo.List()
+}
+
+func (c *webhookController) GetDeliveries(webhookID uint, page, limit int) ([]*entity.WebhookDelivery, error) {
+	if _, err := c.repo.GetByID(webhookID); err != nil {
+		return nil, err
+	}
+	return c.repo.ListDeliveries(webhookID, (page-1)*limit, limit)
+}
+
+func (c *webhookController) Redeliver(webhookID, deliveryID uint) (*entity.WebhookDelivery, error) {
+	delivery, err := c.repo.GetDelivery(webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery.Status = entity.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.LastError = ""
+	if err := c.repo.SaveDelivery(delivery); err != nil {
+		return nil, err
+	}
+	c.sender.Wake()
+	return delivery, nil
+}
+
+func validateWebhook(req *entity.WebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook URL must be an absolute http(s) URL", ErrValidation)
+	}
+
+	known := make(map[string]bool, len(entity.WebhookEvents))
+	for _, event := range entity.WebhookEvents {
+		known[event] = true
+	}
+	for _, event := range req.Events {
+		if !known[event] {
+			return fmt.Errorf("%w: unknown event %q", ErrValidation, event)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+	"gorm.io/gorm"
+)
+
+// fakeWebhookRepo хранит доставки в памяти; остальные методы репозитория тесту не нужны
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+	deliveries map[uint]*entity.WebhookDelivery
+}
+
+func (r *fakeWebhookRepo) GetDelivery(webhookID, id uint) (*entity.WebhookDelivery, error) {
+	d, ok := r.deliveries[id]
+	if !ok || d.WebhookID != webhookID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return d, nil
+}
+
+func (r *fakeWebhookRepo) SaveDelivery(delivery *entity.WebhookDelivery) error {
+	r.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (r *fakeWebhookRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	var due []*entity.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status == entity.DeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			leased := now.Add(lease)
+			d.NextAttemptAt = &leased
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func TestRedeliverRequeuesFailedDelivery(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	failed := &entity.WebhookDelivery{
+		ID:        5,
+		WebhookID: 2,
+		Webhook:   &entity.Webhook{ID: 2, URL: server.URL, Secret: "s3cret", Active: true},
+		Event:     entity.EventPostCreated,
+		Payload:   `{}`,
+		Status:    entity.DeliveryFailed,
+		Attempts:  8,
+		LastError: "receiver responded with 500 Internal Server Error",
+	}
+	repo := &fakeWebhookRepo{deliveries: map[uint]*entity.WebhookDelivery{failed.ID: failed}}
+	sender := service.NewWebhookSender(repo, log.New(io.Discard, "", 0))
+	ctrl := NewWebhookController(repo, sender)
+
+	if _, err := ctrl.Redeliver(3, failed.ID); err == nil {
+		t.Fatal("redelivered a delivery of another webhook")
+	}
+
+	delivery, err := ctrl.Redeliver(2, failed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != entity.DeliveryPending || delivery.Attempts != 0 || delivery.LastError != "" {
+		t.Fatalf("delivery not reset: %+v", delivery)
+	}
+
+	if err := sender.SendDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("receiver called %d times, want 1", hits.Load())
+	}
+	if got := repo.deliveries[failed.ID]; got.Status != entity.DeliverySucceeded || got.Attempts != 1 {
+		t.Fatalf("redelivery not recorded: status %s, attempts %d", got.Status, got.Attempts)
+	}
+}
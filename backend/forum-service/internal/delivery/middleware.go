@@ -2,52 +2,172 @@ package delivery
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
 	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/auth"
+	"gorm.io/gorm"
 )
 
+// UserSyncer подтягивает из auth-service пользователя, событие о котором не дошло
+type UserSyncer interface {
+	SyncUser(ctx context.Context, userID uint) error
+}
+
 type AuthMiddleware struct {
-	logger   *log.Logger
-	userRepo repository.UserRepository
+	logger       *log.Logger
+	tokens       *auth.Validator
+	userRepo     repository.UserRepository
+	sanctionRepo repository.SanctionRepository
+	users        UserSyncer
 }
 
-func NewAuthMiddleware(logger *log.Logger, userRepo repository.UserRepository) *AuthMiddleware {
+func NewAuthMiddleware(
+	logger *log.Logger,
+	tokens *auth.Validator,
+	userRepo repository.UserRepository,
+	sanctionRepo repository.SanctionRepository,
+	users UserSyncer,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		logger:   logger,
-		userRepo: userRepo,
+		logger:       logger,
+		tokens:       tokens,
+		userRepo:     userRepo,
+		sanctionRepo: sanctionRepo,
+		users:        users,
 	}
 }
 
 func (m *AuthMiddleware) Handler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
+		if tokenString == "" && c.GetHeader("Accept") == "text/event-stream" {
+			// EventSource в браузере не умеет передавать заголовки
+			tokenString = c.Query("access_token")
+		}
 		if tokenString == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization required"})
 			return
 		}
 
-		claims, err := auth.ValidateToken(tokenString)
+		claims, err := m.tokens.Validate(tokenString)
 		if err != nil {
 			m.logger.Printf("Invalid token: %v", err)
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
-		user, err := m.userRepo.GetByUsername(claims.Username)
+		user, err := m.userRepo.GetByID(claims.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Токен выдан, но событие о регистрации до форума не дошло: берем снимок
+			if syncErr := m.users.SyncUser(c.Request.Context(), claims.UserID); syncErr != nil {
+				m.logger.Printf("Failed to sync user %d: %v", claims.UserID, syncErr)
+			} else {
+				user, err = m.userRepo.GetByID(claims.UserID)
+			}
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Account is not synchronized yet"})
+			return
+		}
 		if err != nil {
-			m.logger.Printf("User not found: %v", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			m.logger.Printf("Failed to load user %d: %v", claims.UserID, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
 			return
 		}
 
+		sanctions, err := m.sanctionRepo.FindActive(user.ID, time.Now())
+		if err != nil {
+			m.logger.Printf("Failed to load sanctions: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check account status"})
+			return
+		}
+		for _, s := range sanctions {
+			if s.Type == entity.SanctionBan || s.Type == entity.SanctionSuspension {
+				c.AbortWithStatusJSON(http.StatusForbidden, sanctionResponse("account "+s.Type, s))
+				return
+			}
+		}
+
 		ctx := context.WithValue(c.Request.Context(), "userID", user.ID)
 		c.Request = c.Request.WithContext(ctx)
 		c.Set("userID", user.ID)
+		c.Set("sanctions", sanctions)
+		c.Set("userRole", user.Role)
+
+		c.Next()
+	}
+}
+
+// Optional определяет пользователя по токену, если он передан, но не требует авторизации.
+// Используется на публичных маршрутах, ответы которых зависят от читателя.
+func (m *AuthMiddleware) Optional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.GetHeader("Authorization")
+		if tokenString == "" {
+			c.Next()
+			return
+		}
+
+		claims, err := m.tokens.Validate(tokenString)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		user, err := m.userRepo.GetByID(claims.UserID)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		c.Set("userID", user.ID)
+		c.Set("userRole", user.Role)
+		c.Next()
+	}
+}
 
+// WriteAccess запрещает запись пользователям с активным запретом на публикацию.
+// Должен стоять после Handler.
+func (m *AuthMiddleware) WriteAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sanctions, _ := c.Get("sanctions")
+		list, _ := sanctions.([]*entity.Sanction)
+		for _, s := range list {
+			if s.Type == entity.SanctionMute {
+				c.AbortWithStatusJSON(http.StatusForbidden, sanctionResponse("posting muted", s))
+				return
+			}
+		}
 		c.Next()
 	}
 }
+
+// RequireRole пропускает только пользователей с одной из указанных ролей.
+// Должен стоять после Handler.
+func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("userRole")
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	}
+}
+
+func sanctionResponse(message string, s *entity.Sanction) gin.H {
+	resp := gin.H{"error": message, "reason": s.Reason}
+	if s.ExpiresAt != nil {
+		resp["until"] = s.ExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
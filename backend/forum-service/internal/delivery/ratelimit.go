@@ -0,0 +1,85 @@
+package delivery
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter ограничивает частоту запросов одного клиента алгоритмом token bucket
+type RateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // токенов в секунду
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter пропускает perMinute запросов в минуту и до burst подряд. При perMinute = 0 ограничения нет.
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:    float64(perMinute) / 60,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow списывает токен клиента key; если токенов нет, возвращает время до следующего
+func (l *RateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	if l.rate <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep раз в минуту забывает клиентов, чьи корзины уже успели наполниться
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// ByUser ограничивает запросы по вошедшему пользователю. Должен стоять после Handler.
+func (l *RateLimiter) ByUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, retry := l.Allow(strconv.FormatUint(uint64(c.GetUint("userID")), 10), time.Now())
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Avatar - загруженное изображение профиля. У пользователя один аватар,
+// новая загрузка заменяет прежний.
+type Avatar struct {
+	UserID      uint      `gorm:"primaryKey;autoIncrement:false"`
+	ContentType string    `gorm:"size:32;not null"`
+	Data        []byte    `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type Bookmark struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;uniqueIndex:idx_bookmark_user_post;index:idx_bookmark_user_folder"`
+	PostID    uint      `json:"post_id" gorm:"not null;uniqueIndex:idx_bookmark_user_post"`
+	Post      *Post     `json:"post,omitempty" gorm:"foreignKey:PostID"`
+	Folder    string    `json:"folder,omitempty" gorm:"size:64;index:idx_bookmark_user_folder"`
+	Note      string    `json:"note,omitempty" gorm:"size:1000"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type BookmarkRequest struct {
+	Folder string `json:"folder" binding:"max=64"`
+	Note   string `json:"note" binding:"max=1000"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+type Category struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"size:100;not null"`
+	Slug        string    `json:"slug" gorm:"size:100;uniqueIndex;not null"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// UnreadCount - число обсуждений с непрочитанным для авторизованного читателя
+	UnreadCount int64 `json:"unread_count,omitempty" gorm:"-"`
+}
+
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Slug        string `json:"slug" binding:"required,min=2,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+}
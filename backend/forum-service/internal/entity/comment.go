@@ -0,0 +1,15 @@
+package entity
+
+import "gorm.io/gorm"
+
+type Comment struct {
+	gorm.Model
+	PostID   uint   `json:"post_id" gorm:"not null;index"`
+	AuthorID uint   `json:"author_id"`
+	Author   User   `json:"author" gorm:"foreignKey:AuthorID"`
+	Content  string `json:"content"`
+}
+
+type CommentRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=10000"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Draft - черновик поста или ответа, сохраняемый автоматически по мере набора
+type Draft struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"-" gorm:"not null;index"`
+	Title         string    `json:"title" gorm:"size:100"`
+	Content       string    `json:"content"`
+	CategoryID    *uint     `json:"category_id,omitempty"`
+	ReplyToPostID *uint     `json:"reply_to_post_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DraftRequest намеренно мягче PostRequest: черновик может быть незаконченным
+type DraftRequest struct {
+	Title         string `json:"title" binding:"max=100"`
+	Content       string `json:"content" binding:"max=100000"`
+	CategoryID    *uint  `json:"category_id"`
+	ReplyToPostID *uint  `json:"reply_to_post_id"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Mention фиксирует, что пост упоминает пользователя. Запись создается один раз,
+// поэтому повторное редактирование поста не приводит к повторному уведомлению.
+type Mention struct {
+	PostID    uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"primaryKey;index"`
+	Username  string    `gorm:"size:255;not null"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (Mention) TableName() string {
+	return "post_mentions"
+}
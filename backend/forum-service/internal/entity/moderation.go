@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Действия модераторов над обсуждениями
+const (
+	ModerationMove  = "move"
+	ModerationMerge = "merge"
+	ModerationSplit = "split"
+)
+
+// ModerationLog - запись журнала модерации
+type ModerationLog struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	ActorID uint   `json:"actor_id" gorm:"not null;index"`
+	Action  string `json:"action" gorm:"size:32;not null"`
+	PostID  uint   `json:"post_id" gorm:"not null;index"`
+	// TargetID - категория при переносе, целевой пост при объединении, новый пост при разделении
+	TargetID  *uint     `json:"target_id,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	Reason    string    `json:"reason,omitempty" gorm:"size:500"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostRedirect ведет со старого ID объединенного поста на пост, в который он влит
+type PostRedirect struct {
+	OldPostID uint `gorm:"primaryKey;autoIncrement:false"`
+	NewPostID uint `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+type MoveRequest struct {
+	CategoryID uint   `json:"category_id" binding:"required"`
+	Reason     string `json:"reason" binding:"max=500"`
+}
+
+type MergeRequest struct {
+	TargetPostID uint   `json:"target_post_id" binding:"required"`
+	Reason       string `json:"reason" binding:"max=500"`
+}
+
+// SplitRequest выносит комментарии в новое обсуждение. Первый из них становится текстом поста.
+type SplitRequest struct {
+	CommentIDs []uint `json:"comment_ids" binding:"required,min=1,max=500"`
+	Title      string `json:"title" binding:"required,min=3,max=100"`
+	CategoryID *uint  `json:"category_id"`
+	Reason     string `json:"reason" binding:"max=500"`
+}
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Типы уведомлений
+const (
+	NotificationReply      = "reply"
+	NotificationMention    = "mention"
+	NotificationReaction   = "reaction"
+	NotificationModeration = "moderation"
+	NotificationActivity   = "activity"
+)
+
+// EventNotificationCreated рассылается всем репликам, чтобы уведомление дошло
+// до SSE-потока пользователя, открытого на любой из них
+const EventNotificationCreated = "notification.created"
+
+// NotificationTypes - все типы, для которых пользователь может настроить доставку
+var NotificationTypes = []string{
+	NotificationReply,
+	NotificationMention,
+	NotificationReaction,
+	NotificationModeration,
+	NotificationActivity,
+}
+
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"-" gorm:"not null;index"`
+	ActorID   *uint      `json:"actor_id,omitempty"`
+	Type      string     `json:"type" gorm:"size:32;not null"`
+	PostID    *uint      `json:"post_id,omitempty"`
+	CommentID *uint      `json:"comment_id,omitempty"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty" gorm:"index"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificationPreference - настройка доставки уведомлений одного типа.
+// Отсутствие записи означает, что уведомления включены.
+type NotificationPreference struct {
+	UserID  uint   `gorm:"primaryKey"`
+	Type    string `gorm:"primaryKey;size:32"`
+	Enabled bool   `gorm:"not null"`
+}
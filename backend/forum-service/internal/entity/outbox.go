@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// События auth-service, по которым forum-service обновляет свою копию пользователей
+const (
+	EventUserRegistered = "user.registered"
+	EventUserUpdated    = "user.updated"
+	EventUserBanned     = "user.banned"
+	EventUserMuted      = "user.muted"
+	EventSanctionLifted = "user.sanction_lifted"
+)
+
+const AggregatePost = "post"
+
+// EventActivityPublished - активность, которую нужно разослать подписчикам.
+// Рассылка идет в фоне, вне запроса, создавшего пост или комментарий.
+const EventActivityPublished = "activity.published"
+
+// OutboxEvent - событие, записанное в той же транзакции, что и изменение,
+// и ожидающее отправки в шину
+type OutboxEvent struct {
+	ID            int64           `gorm:"primaryKey"`
+	AggregateType string          `gorm:"size:64;not null"`
+	AggregateID   string          `gorm:"size:64;not null"`
+	EventType     string          `gorm:"size:64;not null"`
+	Payload       json.RawMessage `gorm:"type:jsonb;not null"`
+	CreatedAt     time.Time       `gorm:"not null"`
+	PublishedAt   *time.Time      `gorm:"index:idx_outbox_events_unpublished,where:published_at IS NULL"`
+	Attempts      int             `gorm:"not null;default:0"`
+	LastError     string
+}
+
+// ProcessedEvent отмечает уже примененное входящее событие: шина доставляет "хотя бы раз"
+type ProcessedEvent struct {
+	EventID     string    `gorm:"primaryKey;size:128"`
+	ProcessedAt time.Time `gorm:"not null"`
+}
+
+// PostEventPayload - опубликованный пост
+type PostEventPayload struct {
+	ID         uint      `json:"id"`
+	AuthorID   uint      `json:"author_id"`
+	CategoryID *uint     `json:"category_id,omitempty"`
+	Title      string    `json:"title"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewPostCreatedEvent готовит событие о публикации поста для outbox
+func NewPostCreatedEvent(post *Post) (*OutboxEvent, error) {
+	payload, err := json.Marshal(PostEventPayload{
+		ID:         post.ID,
+		AuthorID:   post.AuthorID,
+		CategoryID: post.CategoryID,
+		Title:      post.Title,
+		CreatedAt:  post.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		AggregateType: AggregatePost,
+		AggregateID:   strconv.FormatUint(uint64(post.ID), 10),
+		EventType:     EventPostCreated,
+		Payload:       payload,
+	}, nil
+}
+
+// NewActivityEvent готовит событие о новой активности для outbox
+func NewActivityEvent(activity *Activity) (*OutboxEvent, error) {
+	payload, err := json.Marshal(activity)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		AggregateType: AggregatePost,
+		AggregateID:   strconv.FormatUint(uint64(activity.PostID), 10),
+		EventType:     EventActivityPublished,
+		Payload:       payload,
+	}, nil
+}
+
+// UserEventPayload - состояние пользователя в auth-service после изменения
+type UserEventPayload struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+// SanctionEventPayload - санкция после применения или снятия
+type SanctionEventPayload struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Type      string     `json:"type"`
+	Reason    string     `json:"reason"`
+	StartsAt  time.Time  `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	LiftedAt  *time.Time `json:"lifted_at,omitempty"`
+}
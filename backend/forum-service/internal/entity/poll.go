@@ -0,0 +1,72 @@
+package entity
+
+import "time"
+
+type Poll struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	PostID    uint         `json:"post_id" gorm:"not null;uniqueIndex"`
+	Question  string       `json:"question" gorm:"size:300;not null"`
+	Multiple  bool         `json:"multiple"`
+	Anonymous bool         `json:"anonymous"`
+	ClosesAt  *time.Time   `json:"closes_at,omitempty"`
+	Options   []PollOption `json:"options" gorm:"foreignKey:PollID"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// IsClosed сообщает, завершено ли голосование к моменту now
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
+
+type PollOption struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	PollID   uint   `json:"-" gorm:"not null;index"`
+	Text     string `json:"text" gorm:"size:200;not null"`
+	Position int    `json:"position"`
+}
+
+// PollVoter гарантирует, что пользователь голосует в опросе только один раз
+type PollVoter struct {
+	PollID    uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+type PollVote struct {
+	PollID   uint `gorm:"primaryKey"`
+	UserID   uint `gorm:"primaryKey"`
+	OptionID uint `gorm:"primaryKey;index"`
+}
+
+type PollRequest struct {
+	Question  string     `json:"question" binding:"required,min=3,max=300"`
+	Options   []string   `json:"options" binding:"required,min=2,max=20,dive,required,max=200"`
+	Multiple  bool       `json:"multiple"`
+	Anonymous bool       `json:"anonymous"`
+	ClosesAt  *time.Time `json:"closes_at"`
+}
+
+type VoteRequest struct {
+	OptionIDs []uint `json:"option_ids" binding:"required,min=1"`
+}
+
+// PollResult - опрос с подсчитанными голосами, как его видит конкретный читатель
+type PollResult struct {
+	ID          uint                `json:"id"`
+	PostID      uint                `json:"post_id"`
+	Question    string              `json:"question"`
+	Multiple    bool                `json:"multiple"`
+	Anonymous   bool                `json:"anonymous"`
+	ClosesAt    *time.Time          `json:"closes_at,omitempty"`
+	Closed      bool                `json:"closed"`
+	TotalVoters int64               `json:"total_voters"`
+	Options     []*PollOptionResult `json:"options"`
+	MyVotes     []uint              `json:"my_votes,omitempty"`
+}
+
+type PollOptionResult struct {
+	ID     uint     `json:"id"`
+	Text   string   `json:"text"`
+	Votes  int64    `json:"votes"`
+	Voters []string `json:"voters,omitempty"`
+}
@@ -1,6 +1,17 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Область закрепления поста
+const (
+	PinNone     = ""
+	PinGlobal   = "global"
+	PinCategory = "category"
+)
 
 type Post struct {
 	gorm.Model
@@ -8,9 +19,46 @@ type Post struct {
 	Content  string `json:"content"`
 	AuthorID uint   `json:"author_id"`
 	Author   User   `json:"author" gorm:"foreignKey:AuthorID"`
+
+	CategoryID *uint     `json:"category_id,omitempty" gorm:"index"`
+	Category   *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Tags       []Tag     `json:"tags" gorm:"many2many:post_tags"`
+
+	// Флаги модерации
+	Pin          string     `json:"pin,omitempty" gorm:"size:16;not null;default:''"`
+	PinnedAt     *time.Time `json:"pinned_at,omitempty"`
+	Locked       bool       `json:"locked" gorm:"not null;default:false"`
+	Announcement bool       `json:"announcement" gorm:"not null;default:false"`
+
+	// Score - сумма голосов за пост, ViewCount - число засчитанных просмотров
+	Score     int64 `json:"score" gorm:"not null;default:0"`
+	ViewCount int64 `json:"view_count" gorm:"not null;default:0"`
+
+	// Отложенный пост виден только автору до наступления PublishAt
+	Scheduled bool       `json:"scheduled,omitempty" gorm:"not null;default:false;index"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+
+	// ContentHTML - экранированный текст поста со ссылками на упомянутых пользователей
+	ContentHTML string `json:"content_html" gorm:"-"`
+
+	// Состояние чтения для авторизованного читателя
+	Unread         bool  `json:"unread,omitempty" gorm:"-"`
+	UnreadComments int64 `json:"unread_comments,omitempty" gorm:"-"`
+	Bookmarked     bool  `json:"bookmarked,omitempty" gorm:"-"`
+
+	Poll *PollResult `json:"poll,omitempty" gorm:"-"`
 }
 
 type PostRequest struct {
-	Title   string `json:"title" binding:"required,min=3,max=100"`
-	Content string `json:"content" binding:"required,min=10"`
+	Title      string `json:"title" binding:"required,min=3,max=100"`
+	Content    string `json:"content" binding:"required,min=10"`
+	CategoryID *uint  `json:"category_id"`
+	// PublishAt откладывает публикацию нового поста, прошедшее время означает "сейчас"
+	PublishAt *time.Time `json:"publish_at"`
+	// Tags - названия тегов, при изменении поста nil оставляет прежние теги
+	Tags []string `json:"tags"`
+}
+
+type PinRequest struct {
+	Scope string `json:"scope" binding:"required,oneof=global category"`
 }
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Порядок сортировки ленты постов
+const (
+	SortNew = "new"
+	SortHot = "hot"
+)
+
+// PostRank - рассчитанные периодически оценки поста для сортировки по популярности
+type PostRank struct {
+	PostID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	HotScore      float64   `gorm:"not null;index"`
+	TrendingScore float64   `gorm:"not null;index"`
+	ComputedAt    time.Time `gorm:"not null"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// PostRead - позиция чтения обсуждения: последний прочитанный комментарий.
+// Запись появляется, только когда пользователь открыл пост.
+type PostRead struct {
+	UserID            uint      `gorm:"primaryKey"`
+	PostID            uint      `gorm:"primaryKey;index"`
+	LastReadCommentID uint      `gorm:"not null;default:0"`
+	ReadAt            time.Time `gorm:"not null"`
+}
+
+// CategoryRead - отметка "всё прочитано" для категории. Все посты и комментарии
+// старше ReadAt считаются прочитанными, поэтому PostRead для них удаляются.
+type CategoryRead struct {
+	UserID     uint      `gorm:"primaryKey"`
+	CategoryID uint      `gorm:"primaryKey"`
+	ReadAt     time.Time `gorm:"not null"`
+}
+
+// FirstUnread - позиция первого непрочитанного комментария в обсуждении
+type FirstUnread struct {
+	CommentID *uint `json:"comment_id"`
+	Page      int   `json:"page"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+const (
+	SanctionSuspension = "suspension"
+	SanctionBan        = "ban"
+	SanctionMute       = "mute"
+)
+
+// Sanction - санкция против пользователя. Выдает их auth-service, forum-service
+// держит копию по его событиям в своей таблице и читает её для проверки прав на запись.
+type Sanction struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Type      string     `json:"type"`
+	Reason    string     `json:"reason"`
+	StartsAt  time.Time  `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	LiftedAt  *time.Time `json:"lifted_at,omitempty"`
+	// Version - версия последнего примененного события auth-service
+	Version int64 `json:"-"`
+}
+
+func (Sanction) TableName() string {
+	return "forum_user_sanctions"
+}
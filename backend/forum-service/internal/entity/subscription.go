@@ -0,0 +1,61 @@
+package entity
+
+import "time"
+
+// Объекты, на которые можно подписаться
+const (
+	SubscriptionPost     = "post"
+	SubscriptionCategory = "category"
+)
+
+// Способы доставки активности подписчикам
+const (
+	DeliveryInApp       = "in_app"
+	DeliveryEmailDigest = "email_digest"
+)
+
+type Subscription struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"-" gorm:"not null;uniqueIndex:idx_subscription_target"`
+	TargetType string    `json:"target_type" gorm:"size:16;not null;uniqueIndex:idx_subscription_target;index:idx_subscription_lookup"`
+	TargetID   uint      `json:"target_id" gorm:"not null;uniqueIndex:idx_subscription_target;index:idx_subscription_lookup"`
+	Delivery   string    `json:"delivery" gorm:"size:32;not null;default:in_app"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Title - заголовок поста или название категории, заполняется в списке отслеживаемого
+	Title string `json:"title" gorm:"-"`
+}
+
+type SubscriptionRequest struct {
+	Delivery string `json:"delivery"`
+}
+
+// Activity - новое событие в обсуждении или категории, рассылаемое подписчикам
+type Activity struct {
+	Kind       string `json:"kind"`
+	ActorID    uint   `json:"actor_id"`
+	PostID     uint   `json:"post_id"`
+	CommentID  *uint  `json:"comment_id,omitempty"`
+	CategoryID *uint  `json:"category_id,omitempty"`
+	Message    string `json:"message"`
+
+	// AlreadyNotified - пользователи, уже получившие прямое уведомление (например, автор поста об ответе)
+	AlreadyNotified []uint `json:"already_notified,omitempty"`
+}
+
+const (
+	ActivityPostCreated    = "post.created"
+	ActivityCommentCreated = "comment.created"
+)
+
+// DigestItem - запись, ожидающая отправки в email-дайджесте
+type DigestItem struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	PostID    uint       `gorm:"not null"`
+	Message   string     `gorm:"not null"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	SentAt    *time.Time `gorm:"index"`
+	// ClaimedUntil - до этого момента запись отправляет одна из реплик
+	ClaimedUntil *time.Time
+}
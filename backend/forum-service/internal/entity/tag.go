@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Tag - метка поста. Теги создаются пользователями при публикации,
+// курируемые теги заводят модераторы, и они предлагаются первыми.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"size:32;uniqueIndex;not null"`
+	Curated   bool      `json:"curated" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at"`
+
+	PostCount int64 `json:"post_count" gorm:"->;-:migration"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type TagMergeRequest struct {
+	Into string `json:"into" binding:"required"`
+}
@@ -3,13 +3,63 @@ package entity
 
 import "time"
 
+// Роли пользователей, выдаются в auth-service
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// CanModerate - роль может менять и удалять чужие посты
+func CanModerate(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
+
+// User - проекция пользователя auth-service. Имя, email и роль приходят только из его
+// событий (service.UserProjection) и форумом не меняются; профиль форум ведет сам.
 type User struct {
-	ID        uint      `gorm:"primaryKey"`
-	Username  string    `gorm:"unique;not null"`
-	Email     string    `gorm:"unique;not null"`
-	Password  string    `gorm:"not null"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	Username    string    `json:"username" gorm:"unique;not null"`
+	Email       string    `json:"-" gorm:"size:255"` // адрес для email-дайджестов
+	Role        string    `json:"role" gorm:"size:32;not null;default:user"`
+	DisplayName string    `json:"display_name" gorm:"size:64"`
+	Bio         string    `json:"bio,omitempty" gorm:"size:1000"`
+	AvatarURL   string    `json:"avatar_url,omitempty" gorm:"size:255"`
+	Location    string    `json:"location,omitempty" gorm:"size:100"`
+	Website     string    `json:"website,omitempty" gorm:"size:255"`
+	Signature   string    `json:"signature,omitempty" gorm:"size:300"`
+	CreatedAt   time.Time `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	// Version - версия последнего примененного события auth-service
+	Version int64 `json:"-" gorm:"not null;default:0"`
+}
+
+// TableName - таблица users принадлежит auth-service, у форума своя
+func (User) TableName() string {
+	return "authors"
+}
+
+// Profile - публичное представление пользователя
+type Profile struct {
+	ID          uint      `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	AvatarURL   string    `json:"avatar_url"`
+	Location    string    `json:"location"`
+	Website     string    `json:"website"`
+	Signature   string    `json:"signature"`
+	PostCount   int64     `json:"post_count"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// ProfileUpdateRequest - частичное обновление профиля, nil-поля не меняются
+type ProfileUpdateRequest struct {
+	DisplayName *string `json:"display_name" binding:"omitempty,max=64"`
+	Bio         *string `json:"bio" binding:"omitempty,max=1000"`
+	Location    *string `json:"location" binding:"omitempty,max=100"`
+	Website     *string `json:"website" binding:"omitempty,max=255"`
+	Signature   *string `json:"signature" binding:"omitempty,max=300"`
 }
 
 // chat_message.go
@@ -20,22 +70,3 @@ type ChatMessage struct {
 	Content   string    `gorm:"not null"`
 	Timestamp time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 }
-
-// token.go
-type Token struct {
-	ID        uint      `gorm:"primaryKey"`
-	UserID    uint      `gorm:"not null"`
-	Token     string    `gorm:"unique;not null"`
-	ExpiresAt time.Time `gorm:"not null"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
-}
-
-// email_verification.go
-type EmailVerification struct {
-	ID        uint      `gorm:"primaryKey"`
-	UserID    uint      `gorm:"not null"`
-	Code      string    `gorm:"not null"`
-	ExpiresAt time.Time `gorm:"not null"`
-	Verified  bool      `gorm:"default:false"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
-}
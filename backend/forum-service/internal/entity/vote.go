@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// PostVote - голос пользователя за пост: +1 или -1
+type PostVote struct {
+	PostID  uint      `gorm:"primaryKey"`
+	UserID  uint      `gorm:"primaryKey;index"`
+	Value   int       `gorm:"not null"`
+	VotedAt time.Time `gorm:"not null;index"`
+}
+
+type PostVoteRequest struct {
+	Value int `json:"value" binding:"required,oneof=1 -1"`
+}
@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+// События, на которые можно подписать вебхук
+const (
+	EventPostCreated    = "post.created"
+	EventPostUpdated    = "post.updated"
+	EventPostDeleted    = "post.deleted"
+	EventCommentCreated = "comment.created"
+)
+
+var WebhookEvents = []string{EventPostCreated, EventPostUpdated, EventPostDeleted, EventCommentCreated}
+
+// Состояния доставки вебхука
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	URL       string    `json:"url" gorm:"size:2048;not null"`
+	Secret    string    `json:"-" gorm:"size:128;not null"`
+	Events    []string  `json:"events" gorm:"serializer:json;not null"`
+	Active    bool      `json:"active" gorm:"not null;default:true"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribed сообщает, подписан ли вебхук на событие
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookWithSecret возвращается только при создании: секрет больше нигде не показывается
+type WebhookWithSecret struct {
+	*Webhook
+	Secret string `json:"secret"`
+}
+
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1"`
+	// Secret можно не указывать, тогда он будет сгенерирован
+	Secret string `json:"secret" binding:"omitempty,min=16,max=128"`
+	Active *bool  `json:"active"`
+}
+
+// WebhookDelivery - одна отправка события на вебхук и результат последней попытки
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	WebhookID      uint       `json:"webhook_id" gorm:"not null;index"`
+	Webhook        *Webhook   `json:"-" gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+	Event          string     `json:"event" gorm:"size:64;not null"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"size:16;not null;index:idx_webhook_delivery_due"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" gorm:"index:idx_webhook_delivery_due"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty" gorm:"size:1000"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
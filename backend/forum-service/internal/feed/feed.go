@@ -0,0 +1,199 @@
+// Package feed формирует ленты RSS 2.0 и Atom 1.0.
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Форматы лент
+const (
+	FormatAtom = "atom"
+	FormatRSS  = "rss"
+)
+
+// Feed - лента в независимом от формата виде
+type Feed struct {
+	ID          string
+	Title       string
+	Description string
+	Link        string
+	SelfLink    string
+	Updated     time.Time
+	Entries     []Entry
+}
+
+type Entry struct {
+	ID          string
+	Title       string
+	Link        string
+	Author      string
+	Category    string
+	ContentHTML string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// ETag меняется при любом изменении состава или времени обновления записей
+func (f *Feed) ETag(format string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%d", format, f.ID, f.Updated.UnixNano())
+	for _, e := range f.Entries {
+		fmt.Fprintf(h, "|%s@%d", e.ID, e.Updated.UnixNano())
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// Encode возвращает ленту в формате format и ее Content-Type
+func (f *Feed) Encode(format string) ([]byte, string, error) {
+	var doc interface{}
+	var contentType string
+	switch format {
+	case FormatAtom:
+		doc, contentType = f.atom(), "application/atom+xml; charset=utf-8"
+	case FormatRSS:
+		doc, contentType = f.rss(), "application/rss+xml; charset=utf-8"
+	default:
+		return nil, "", fmt.Errorf("unknown feed format %q", format)
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+	return append([]byte(xml.Header), body...), contentType, nil
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Link      atomLink      `xml:"link"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Author    atomAuthor    `xml:"author"`
+	Category  *atomCategory `xml:"category,omitempty"`
+	Content   atomContent   `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func (f *Feed) atom() *atomFeed {
+	doc := &atomFeed{
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.SelfLink, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			ID:        e.ID,
+			Title:     e.Title,
+			Link:      atomLink{Href: e.Link, Rel: "alternate", Type: "text/html"},
+			Published: e.Published.UTC().Format(time.RFC3339),
+			Updated:   e.Updated.UTC().Format(time.RFC3339),
+			Author:    atomAuthor{Name: e.Author},
+			Content:   atomContent{Type: "html", Body: e.ContentHTML},
+		}
+		if e.Category != "" {
+			entry.Category = &atomCategory{Term: e.Category}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          rssSelf   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssSelf - рекомендуемая ссылка на саму ленту из пространства имен Atom
+type rssSelf struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Category    string  `xml:"category,omitempty"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func (f *Feed) rss() *rssFeed {
+	doc := &rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+			Self:          rssSelf{Href: f.SelfLink, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	for _, e := range f.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{Value: e.ID},
+			Category:    e.Category,
+			Description: e.ContentHTML,
+			PubDate:     e.Published.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return doc
+}
@@ -0,0 +1,65 @@
+// Package mention разбирает и отображает упоминания вида @username в тексте постов.
+package mention
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MaxPerPost ограничивает число упоминаний, обрабатываемых в одном посте
+const MaxPerPost = 20
+
+// Упоминание начинается в начале строки или после символа, не входящего в имя,
+// чтобы адреса вида user@example.com не считались упоминаниями
+var pattern = regexp.MustCompile(`(^|[^\w@])@([A-Za-z0-9_][A-Za-z0-9_.\-]{0,31})`)
+
+// Parse возвращает уникальные имена пользователей, упомянутые в content, в порядке появления
+func Parse(content string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, m := range pattern.FindAllStringSubmatch(content, -1) {
+		name := trim(m[2])
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		usernames = append(usernames, name)
+		if len(usernames) == MaxPerPost {
+			break
+		}
+	}
+	return usernames
+}
+
+// Render экранирует content как HTML и превращает упоминания известных
+// пользователей в ссылки на их профили
+func Render(content string, known map[string]bool) string {
+	var b strings.Builder
+	last := 0
+	for _, idx := range pattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := idx[4], idx[5]
+		name := trim(content[start:end])
+		if !known[name] {
+			continue
+		}
+		end = start + len(name)
+
+		// Символ перед "@" относится к префиксу совпадения
+		b.WriteString(html.EscapeString(content[last : start-1]))
+		b.WriteString(`<a class="mention" href="/users/`)
+		b.WriteString(url.PathEscape(name))
+		b.WriteString(`">@`)
+		b.WriteString(html.EscapeString(name))
+		b.WriteString(`</a>`)
+		last = end
+	}
+	b.WriteString(html.EscapeString(content[last:]))
+	return b.String()
+}
+
+// trim убирает знаки препинания, которыми обычно заканчивается предложение
+func trim(name string) string {
+	return strings.TrimRight(name, ".-")
+}
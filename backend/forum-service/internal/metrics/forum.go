@@ -0,0 +1,13 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PostsCreated считает новые посты; отложенные учитываются при создании,
+// а не при публикации
+var PostsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "forum_posts_created_total",
+	Help: "Created posts, by whether publication was scheduled.",
+}, []string{"scheduled"})
@@ -0,0 +1,56 @@
+// Package metrics - метрики Prometheus: HTTP, пул соединений с базой и
+// доменные счетчики. Все метрики регистрируются в реестре по умолчанию.
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route template and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// HTTP считает запросы. Маршрут берется шаблоном (/posts/:id), а не путем,
+// чтобы число рядов не росло вместе с числом записей.
+func HTTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler отдает метрики в текстовом формате Prometheus
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// RegisterDB публикует sql.DB.Stats: открытые, занятые и простаивающие
+// соединения, ожидания свободного соединения
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
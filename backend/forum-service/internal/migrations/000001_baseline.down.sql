@@ -0,0 +1,5 @@
+-- Базовая схема необратима: откат удалил бы все данные форума
+DO $$
+BEGIN
+    RAISE EXCEPTION 'baseline migration cannot be reverted';
+END $$;
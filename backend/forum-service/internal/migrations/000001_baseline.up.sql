@@ -0,0 +1,333 @@
+-- Базовая схема форума в том виде, в каком ее создавал gorm AutoMigrate.
+-- Все операторы идемпотентны: база, уже размеченная AutoMigrate, переходит
+-- на версионные миграции без изменений. Данные здесь не переносятся;
+-- перенос пользователей в authors - миграция 000002.
+
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL,
+    name VARCHAR(32) NOT NULL,
+    curated BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (name);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id BIGSERIAL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    title TEXT,
+    content TEXT,
+    author_id BIGINT,
+    category_id BIGINT,
+    pin VARCHAR(16) NOT NULL DEFAULT '',
+    pinned_at TIMESTAMPTZ,
+    locked BOOLEAN NOT NULL DEFAULT false,
+    announcement BOOLEAN NOT NULL DEFAULT false,
+    score BIGINT NOT NULL DEFAULT 0,
+    view_count BIGINT NOT NULL DEFAULT 0,
+    scheduled BOOLEAN NOT NULL DEFAULT false,
+    publish_at TIMESTAMPTZ,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_posts_category FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+-- auth-service создает собственную урезанную posts, если запускается первым
+ALTER TABLE posts ALTER COLUMN id TYPE BIGINT;
+ALTER TABLE posts ALTER COLUMN author_id TYPE BIGINT;
+ALTER TABLE posts ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+ALTER TABLE posts ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS category_id BIGINT;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS pin VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMPTZ;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS locked BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS announcement BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS score BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS scheduled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_posts_scheduled ON posts (scheduled);
+CREATE INDEX IF NOT EXISTS idx_posts_category_id ON posts (category_id);
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id BIGINT,
+    tag_id BIGINT,
+    PRIMARY KEY (post_id,tag_id),
+    CONSTRAINT fk_post_tags_post FOREIGN KEY (post_id) REFERENCES posts(id),
+    CONSTRAINT fk_post_tags_tag FOREIGN KEY (tag_id) REFERENCES tags(id)
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id BIGSERIAL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    post_id BIGINT NOT NULL,
+    author_id BIGINT,
+    content TEXT,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments (post_id);
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments (deleted_at);
+
+CREATE TABLE IF NOT EXISTS post_mentions (
+    post_id BIGINT,
+    user_id BIGINT,
+    username VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id,user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_post_mentions_user_id ON post_mentions (user_id);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id BIGINT NOT NULL,
+    delivery VARCHAR(32) NOT NULL DEFAULT 'in_app',
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_subscription_lookup ON subscriptions (target_type,target_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_target ON subscriptions (user_id,target_type,target_id);
+
+CREATE TABLE IF NOT EXISTS digest_items (
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    post_id BIGINT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_digest_items_sent_at ON digest_items (sent_at);
+CREATE INDEX IF NOT EXISTS idx_digest_items_user_id ON digest_items (user_id);
+
+CREATE TABLE IF NOT EXISTS post_reads (
+    user_id BIGINT,
+    post_id BIGINT,
+    last_read_comment_id BIGINT NOT NULL DEFAULT 0,
+    read_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id,post_id)
+);
+CREATE INDEX IF NOT EXISTS idx_post_reads_post_id ON post_reads (post_id);
+
+CREATE TABLE IF NOT EXISTS category_reads (
+    user_id BIGINT,
+    category_id BIGINT,
+    read_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id,category_id)
+);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    post_id BIGINT NOT NULL,
+    folder VARCHAR(64),
+    note VARCHAR(1000),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_bookmarks_post FOREIGN KEY (post_id) REFERENCES posts(id)
+);
+CREATE INDEX IF NOT EXISTS idx_bookmark_user_folder ON bookmarks (user_id,folder);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_user_post ON bookmarks (user_id,post_id);
+
+CREATE TABLE IF NOT EXISTS drafts (
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    title VARCHAR(100),
+    content TEXT,
+    category_id BIGINT,
+    reply_to_post_id BIGINT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_drafts_user_id ON drafts (user_id);
+
+CREATE TABLE IF NOT EXISTS moderation_logs (
+    id BIGSERIAL,
+    actor_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    post_id BIGINT NOT NULL,
+    target_id BIGINT,
+    details TEXT,
+    reason VARCHAR(500),
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_logs_post_id ON moderation_logs (post_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_logs_actor_id ON moderation_logs (actor_id);
+
+CREATE TABLE IF NOT EXISTS post_votes (
+    post_id BIGINT,
+    user_id BIGINT,
+    value BIGINT NOT NULL,
+    voted_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id,user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_post_votes_voted_at ON post_votes (voted_at);
+CREATE INDEX IF NOT EXISTS idx_post_votes_user_id ON post_votes (user_id);
+
+CREATE TABLE IF NOT EXISTS post_ranks (
+    post_id BIGINT,
+    hot_score DECIMAL NOT NULL,
+    trending_score DECIMAL NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id)
+);
+CREATE INDEX IF NOT EXISTS idx_post_ranks_trending_score ON post_ranks (trending_score);
+CREATE INDEX IF NOT EXISTS idx_post_ranks_hot_score ON post_ranks (hot_score);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BIGSERIAL,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    events TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_by BIGINT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL,
+    webhook_id BIGINT NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    last_status_code BIGINT,
+    last_error VARCHAR(1000),
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_webhook_deliveries_webhook FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_due ON webhook_deliveries (status,next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL,
+    aggregate_type VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished ON outbox_events (published_at) WHERE published_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id VARCHAR(128),
+    processed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (event_id)
+);
+
+CREATE TABLE IF NOT EXISTS post_redirects (
+    old_post_id BIGINT,
+    new_post_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (old_post_id)
+);
+CREATE INDEX IF NOT EXISTS idx_post_redirects_new_post_id ON post_redirects (new_post_id);
+
+CREATE TABLE IF NOT EXISTS polls (
+    id BIGSERIAL,
+    post_id BIGINT NOT NULL,
+    question VARCHAR(300) NOT NULL,
+    multiple BOOLEAN,
+    anonymous BOOLEAN,
+    closes_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_polls_post_id ON polls (post_id);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id BIGSERIAL,
+    poll_id BIGINT NOT NULL,
+    TEXT VARCHAR(200) NOT NULL,
+    "position" BIGINT,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_polls_options FOREIGN KEY (poll_id) REFERENCES polls(id)
+);
+CREATE INDEX IF NOT EXISTS idx_poll_options_poll_id ON poll_options (poll_id);
+
+CREATE TABLE IF NOT EXISTS poll_voters (
+    poll_id BIGINT,
+    user_id BIGINT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id,user_id)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id BIGINT,
+    user_id BIGINT,
+    option_id BIGINT,
+    PRIMARY KEY (poll_id,user_id,option_id)
+);
+CREATE INDEX IF NOT EXISTS idx_poll_votes_option_id ON poll_votes (option_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    actor_id BIGINT,
+    type VARCHAR(32) NOT NULL,
+    post_id BIGINT,
+    comment_id BIGINT,
+    message TEXT,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_read_at ON notifications (read_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id BIGINT,
+    type VARCHAR(32),
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id,type)
+);
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    username TEXT NOT NULL,
+    content TEXT NOT NULL,
+    "timestamp" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+ALTER TABLE chat_messages ALTER COLUMN id TYPE BIGINT;
+ALTER TABLE chat_messages ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE chat_messages ALTER COLUMN "timestamp" TYPE TIMESTAMPTZ;
+
+-- posts, созданная auth-service, не имеет ключа на категории
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_posts_category' AND conrelid = 'posts'::regclass) THEN
+        ALTER TABLE posts ADD CONSTRAINT fk_posts_category FOREIGN KEY (category_id) REFERENCES categories(id);
+    END IF;
+END $$;
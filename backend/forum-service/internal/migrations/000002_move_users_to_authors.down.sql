@@ -0,0 +1,28 @@
+-- Профили возвращаются в users, если там остались их столбцы, ключи
+-- постов и комментариев снова указывают на users
+DO $$
+BEGIN
+    ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_posts_author;
+    ALTER TABLE comments DROP CONSTRAINT IF EXISTS fk_comments_author;
+
+    IF to_regclass('users') IS NULL THEN
+        RETURN;
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'display_name') THEN
+        UPDATE users SET
+            display_name = a.display_name,
+            bio = a.bio,
+            avatar_url = a.avatar_url,
+            location = a.location,
+            website = a.website,
+            signature = a.signature
+        FROM authors a
+        WHERE users.id = a.id;
+    END IF;
+
+    ALTER TABLE posts ADD CONSTRAINT fk_posts_author FOREIGN KEY (author_id) REFERENCES users(id);
+    ALTER TABLE comments ADD CONSTRAINT fk_comments_author FOREIGN KEY (author_id) REFERENCES users(id);
+END $$;
+
+DROP TABLE IF EXISTS authors;
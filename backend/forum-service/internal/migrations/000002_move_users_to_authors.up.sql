@@ -0,0 +1,63 @@
+-- Форум больше не делит таблицу users с auth-service: имя, email и роль
+-- приходят событиями в authors, профиль форум ведет там же. Повторный запуск
+-- на уже перенесенной базе ничего не меняет.
+
+CREATE TABLE IF NOT EXISTS authors (
+    id BIGINT,
+    username TEXT NOT NULL,
+    email VARCHAR(255),
+    role VARCHAR(32) NOT NULL DEFAULT 'user',
+    display_name VARCHAR(64),
+    bio VARCHAR(1000),
+    avatar_url VARCHAR(255),
+    location VARCHAR(100),
+    website VARCHAR(255),
+    signature VARCHAR(300),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT uni_authors_username UNIQUE (username)
+);
+
+-- Профили из users, которую форум раньше делил с auth-service
+DO $$
+DECLARE
+    columns TEXT := 'id, username, email, role';
+BEGIN
+    IF to_regclass('users') IS NULL THEN
+        RETURN;
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'display_name') THEN
+        columns := columns || ', display_name, bio, avatar_url, location, website, signature, created_at, updated_at';
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'role') THEN
+        columns := replace(columns, ', role', '');
+    END IF;
+    EXECUTE format('INSERT INTO authors (%1$s) SELECT %1$s FROM users ON CONFLICT (id) DO NOTHING', columns);
+END $$;
+
+-- Внешние ключи форума на users заменяются ключами на authors; ключи
+-- auth-service названы иначе (*_fkey) и остаются на месте
+DO $$
+DECLARE
+    fk RECORD;
+BEGIN
+    IF to_regclass('users') IS NOT NULL THEN
+        FOR fk IN
+            SELECT conrelid::regclass::text AS table_name, conname
+            FROM pg_constraint
+            WHERE contype = 'f' AND confrelid = 'users'::regclass AND conname LIKE 'fk\_%'
+        LOOP
+            EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', fk.table_name, fk.conname);
+        END LOOP;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_posts_author' AND conrelid = 'posts'::regclass) THEN
+        ALTER TABLE posts ADD CONSTRAINT fk_posts_author FOREIGN KEY (author_id) REFERENCES authors(id);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_comments_author' AND conrelid = 'comments'::regclass) THEN
+        ALTER TABLE comments ADD CONSTRAINT fk_comments_author FOREIGN KEY (author_id) REFERENCES authors(id);
+    END IF;
+END $$;
@@ -0,0 +1 @@
+ALTER TABLE digest_items DROP COLUMN IF EXISTS claimed_until;
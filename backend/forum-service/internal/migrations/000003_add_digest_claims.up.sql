@@ -0,0 +1,4 @@
+-- Рассыльщик дайджеста захватывает записи на время отправки, чтобы
+-- несколько реплик не отправляли одно и то же письмо
+
+ALTER TABLE digest_items ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ;
@@ -0,0 +1,24 @@
+-- Форум держит копию санкций в своей таблице: user_sanctions принадлежит
+-- auth-service, и запись в нее из проекции смешала бы данные двух сервисов
+
+CREATE TABLE IF NOT EXISTS forum_user_sanctions (
+    id BIGINT,
+    user_id BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    reason TEXT NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ,
+    lifted_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_forum_user_sanctions_user_id ON forum_user_sanctions (user_id);
+
+-- Санкции, выданные до перехода на события, в общей базе берутся из таблицы auth-service
+DO $$
+BEGIN
+    IF to_regclass('user_sanctions') IS NOT NULL THEN
+        INSERT INTO forum_user_sanctions (id, user_id, type, reason, starts_at, expires_at, lifted_at)
+        SELECT id, user_id, type, reason, starts_at, expires_at, lifted_at FROM user_sanctions
+        ON CONFLICT (id) DO NOTHING;
+    END IF;
+END $$;
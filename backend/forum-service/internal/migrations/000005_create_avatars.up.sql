@@ -0,0 +1,9 @@
+-- Аватары хранятся в базе: локальный диск одной реплики не виден остальным
+
+CREATE TABLE IF NOT EXISTS avatars (
+    user_id BIGINT,
+    content_type VARCHAR(32) NOT NULL,
+    data BYTEA NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE forum_user_sanctions DROP COLUMN IF EXISTS version;
+ALTER TABLE authors DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- Версия - номер события auth-service, последним изменившего запись. События могут
+-- прийти не по порядку, и более старое не должно перезаписать более новое.
+
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE forum_user_sanctions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
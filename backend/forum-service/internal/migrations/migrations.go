@@ -0,0 +1,96 @@
+// Package migrations содержит SQL-миграции forum-service, встроенные в бинарник
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Table - таблица версий форума. База общая с auth-service, и его
+// schema_migrations занята.
+const Table = "forum_schema_migrations"
+
+// New собирает мигратор поверх открытого соединения.
+// Close мигратора закрывает и db, поэтому вызывать его стоит только перед выходом.
+func New(db *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, err
+	}
+	driver, err := pgx.WithInstance(db, &pgx.Config{MigrationsTable: Table})
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithInstance("iofs", source, "pgx5", driver)
+}
+
+// Up применяет все новые миграции; отсутствие новых ошибкой не считается
+func Up(db *sql.DB) error {
+	m, err := New(db)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Verify - проверка готовности: база не отстает от встроенных миграций и не
+// осталась грязной после сбоя. База новее бинарника допустима: во время
+// выкатки старые реплики работают, пока их не заменят.
+func Verify(ctx context.Context, db *sql.DB) error {
+	latest, err := latestVersion()
+	if err != nil {
+		return err
+	}
+
+	var version uint
+	var dirty bool
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM "+Table+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no migrations applied, expected version %d", latest)
+	}
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d failed and left the schema dirty", version)
+	}
+	if version < latest {
+		return fmt.Errorf("database at version %d, expected %d", version, latest)
+	}
+	return nil
+}
+
+func latestVersion() (uint, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return 0, err
+	}
+	version, err := source.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
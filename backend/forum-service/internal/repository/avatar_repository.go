@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AvatarRepository interface {
+	// Save создает аватар или заменяет прежний
+	Save(avatar *entity.Avatar) error
+	Get(userID uint) (*entity.Avatar, error)
+}
+
+type avatarRepository struct {
+	db *gorm.DB
+}
+
+func NewAvatarRepository(db *gorm.DB) AvatarRepository {
+	return &avatarRepository{db: db}
+}
+
+func (r *avatarRepository) Save(avatar *entity.Avatar) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content_type", "data", "updated_at"}),
+	}).Create(avatar).Error
+}
+
+func (r *avatarRepository) Get(userID uint) (*entity.Avatar, error) {
+	var avatar entity.Avatar
+	if err := r.db.First(&avatar, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &avatar, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkRepository interface {
+	// Save создает закладку или обновляет папку и заметку существующей
+	Save(bookmark *entity.Bookmark) error
+	Delete(userID, postID uint) error
+	List(userID uint, folder string, offset, limit int) ([]*entity.Bookmark, error)
+	Count(userID uint, folder string) (int64, error)
+	// BookmarkedPostIDs возвращает те из postIDs, что есть в закладках пользователя
+	BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error)
+}
+
+type bookmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(db *gorm.DB) BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+func (r *bookmarkRepository) Save(bookmark *entity.Bookmark) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"folder", "note", "updated_at"}),
+	}).Create(bookmark).Error
+}
+
+func (r *bookmarkRepository) Delete(userID, postID uint) error {
+	res := r.db.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&entity.Bookmark{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *bookmarkRepository) List(userID uint, folder string, offset, limit int) ([]*entity.Bookmark, error) {
+	var bookmarks []*entity.Bookmark
+	err := r.scope(userID, folder).
+		Preload("Post").
+		Preload("Post.Author").
+		Offset(offset).
+		Limit(limit).
+		Order("bookmarks.created_at DESC").
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+func (r *bookmarkRepository) Count(userID uint, folder string) (int64, error) {
+	var count int64
+	err := r.scope(userID, folder).Model(&entity.Bookmark{}).Count(&count).Error
+	return count, err
+}
+
+func (r *bookmarkRepository) BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	result := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&entity.Bookmark{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+func (r *bookmarkRepository) scope(userID uint, folder string) *gorm.DB {
+	query := r.db.
+		Joins("JOIN posts ON posts.id = bookmarks.post_id").
+		Scopes(visibleTo(userID)).
+		Where("bookmarks.user_id = ?", userID)
+	if folder != "" {
+		query = query.Where("bookmarks.folder = ?", folder)
+	}
+	return query
+}
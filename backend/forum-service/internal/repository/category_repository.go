@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	Create(category *entity.Category) error
+	GetAll() ([]*entity.Category, error)
+	GetByID(id uint) (*entity.Category, error)
+	GetBySlug(slug string) (*entity.Category, error)
+	GetByIDs(ids []uint) ([]*entity.Category, error)
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(category *entity.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *categoryRepository) GetAll() ([]*entity.Category, error) {
+	var categories []*entity.Category
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) GetByID(id uint) (*entity.Category, error) {
+	var category entity.Category
+	if err := r.db.First(&category, id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetBySlug(slug string) (*entity.Category, error) {
+	var category entity.Category
+	if err := r.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetByIDs(ids []uint) ([]*entity.Category, error) {
+	var categories []*entity.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&categories).Error
+	return categories, err
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(comment *entity.Comment) error
+	GetByID(id uint) (*entity.Comment, error)
+	GetByPost(postID uint, offset, limit int) ([]*entity.Comment, error)
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(comment *entity.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *commentRepository) GetByID(id uint) (*entity.Comment, error) {
+	var comment entity.Comment
+	if err := r.db.Preload("Author").First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepository) GetByPost(postID uint, offset, limit int) ([]*entity.Comment, error) {
+	var comments []*entity.Comment
+	err := r.db.Preload("Author").
+		Where("post_id = ?", postID).
+		Offset(offset).
+		Limit(limit).
+		Order("created_at ASC").
+		Find(&comments).Error
+	return comments, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+type DigestRepository interface {
+	Add(item *entity.DigestItem) error
+	// ClaimPending захватывает неотправленные записи на время lease,
+	// чтобы их не взяла другая реплика
+	ClaimPending(now time.Time, lease time.Duration, limit int) ([]*entity.DigestItem, error)
+	MarkSent(ids []uint, at time.Time) error
+}
+
+type digestRepository struct {
+	db *gorm.DB
+}
+
+func NewDigestRepository(db *gorm.DB) DigestRepository {
+	return &digestRepository{db: db}
+}
+
+func (r *digestRepository) Add(item *entity.DigestItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *digestRepository) ClaimPending(now time.Time, lease time.Duration, limit int) ([]*entity.DigestItem, error) {
+	var ids []uint
+	err := r.db.Raw(`
+		UPDATE digest_items SET claimed_until = ?
+		WHERE id IN (
+			SELECT id FROM digest_items
+			WHERE sent_at IS NULL AND (claimed_until IS NULL OR claimed_until <= ?)
+			ORDER BY user_id, created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		now.Add(lease), now, limit,
+	).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var items []*entity.DigestItem
+	err = r.db.Where("id IN ?", ids).Order("user_id ASC, created_at ASC").Find(&items).Error
+	return items, err
+}
+
+func (r *digestRepository) MarkSent(ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&entity.DigestItem{}).
+		Where("id IN ?", ids).
+		Update("sent_at", at).Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+type DraftRepository interface {
+	Create(draft *entity.Draft) error
+	Update(draft *entity.Draft) error
+	GetByID(userID, id uint) (*entity.Draft, error)
+	List(userID uint, offset, limit int) ([]*entity.Draft, error)
+	Delete(userID, id uint) error
+}
+
+type draftRepository struct {
+	db *gorm.DB
+}
+
+func NewDraftRepository(db *gorm.DB) DraftRepository {
+	return &draftRepository{db: db}
+}
+
+func (r *draftRepository) Create(draft *entity.Draft) error {
+	return r.db.Create(draft).Error
+}
+
+func (r *draftRepository) Update(draft *entity.Draft) error {
+	return r.db.Model(draft).
+		Select("title", "content", "category_id", "reply_to_post_id", "updated_at").
+		Updates(draft).Error
+}
+
+// GetByID ищет только среди черновиков пользователя: чужой черновик считается несуществующим
+func (r *draftRepository) GetByID(userID, id uint) (*entity.Draft, error) {
+	var draft entity.Draft
+	if err := r.db.Where("user_id = ?", userID).First(&draft, id).Error; err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (r *draftRepository) List(userID uint, offset, limit int) ([]*entity.Draft, error) {
+	var drafts []*entity.Draft
+	err := r.db.Where("user_id = ?", userID).
+		Offset(offset).
+		Limit(limit).
+		Order("updated_at DESC").
+		Find(&drafts).Error
+	return drafts, err
+}
+
+func (r *draftRepository) Delete(userID, id uint) error {
+	res := r.db.Where("user_id = ?", userID).Delete(&entity.Draft{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
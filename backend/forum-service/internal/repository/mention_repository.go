@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MentionRepository interface {
+	// Add сохраняет упоминание и сообщает, было ли оно новым
+	Add(mention *entity.Mention) (bool, error)
+	GetByPosts(postIDs []uint) ([]*entity.Mention, error)
+}
+
+type mentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) MentionRepository {
+	return &mentionRepository{db: db}
+}
+
+func (r *mentionRepository) Add(mention *entity.Mention) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(mention)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *mentionRepository) GetByPosts(postIDs []uint) ([]*entity.Mention, error) {
+	var mentions []*entity.Mention
+	if len(postIDs) == 0 {
+		return mentions, nil
+	}
+	err := r.db.Where("post_id IN ?", postIDs).Find(&mentions).Error
+	return mentions, err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	Create(notification *entity.Notification) error
+	List(userID uint, unreadOnly bool, offset, limit int) ([]*entity.Notification, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID, id uint, at time.Time) error
+	MarkAllRead(userID uint, at time.Time) error
+	GetPreferences(userID uint) ([]*entity.NotificationPreference, error)
+	SavePreferences(prefs []*entity.NotificationPreference) error
+	IsEnabled(userID uint, notificationType string) (bool, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(notification *entity.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *notificationRepository) List(userID uint, unreadOnly bool, offset, limit int) ([]*entity.Notification, error) {
+	var notifications []*entity.Notification
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(userID, id uint, at time.Time) error {
+	res := r.db.Model(&entity.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", at))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(userID uint, at time.Time) error {
+	return r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at).Error
+}
+
+func (r *notificationRepository) GetPreferences(userID uint) ([]*entity.NotificationPreference, error) {
+	var prefs []*entity.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&prefs).Error
+	return prefs, err
+}
+
+func (r *notificationRepository) SavePreferences(prefs []*entity.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+	}).Create(&prefs).Error
+}
+
+func (r *notificationRepository) IsEnabled(userID uint, notificationType string) (bool, error) {
+	var pref entity.NotificationPreference
+	err := r.db.Where("user_id = ? AND type = ?", userID, notificationType).Limit(1).Find(&pref).Error
+	if err != nil {
+		return false, err
+	}
+	if pref.UserID == 0 {
+		return true, nil
+	}
+	return pref.Enabled, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+// outboxLockKey - ключ advisory-блокировки: события отправляет одна реплика за раз,
+// поэтому порядок в шине совпадает с порядком записи
+const outboxLockKey = 7310002
+
+type OutboxRepository interface {
+	Add(event *entity.OutboxEvent) error
+	// PublishPending передает publish до limit неотправленных событий по порядку
+	// и отмечает отправленные. На первой ошибке останавливается, чтобы не нарушить порядок.
+	PublishPending(limit int, publish func(event *entity.OutboxEvent) error) (int, error)
+	CountPending(ctx context.Context) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Add(event *entity.OutboxEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *outboxRepository) CountPending(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("published_at IS NULL").Count(&n).Error
+	return n, err
+}
+
+func (r *outboxRepository) PublishPending(limit int, publish func(event *entity.OutboxEvent) error) (int, error) {
+	published := 0
+	var publishErr error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var pending []*entity.OutboxEvent
+		err := tx.Where("published_at IS NULL").Order("id").Limit(limit).Find(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		for _, e := range pending {
+			if publishErr = publish(e); publishErr != nil {
+				return tx.Model(e).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": publishErr.Error(),
+				}).Error
+			}
+			if err := tx.Model(e).Update("published_at", time.Now()).Error; err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyVoted возвращается при повторном голосовании в опросе
+var ErrAlreadyVoted = errors.New("already voted in this poll")
+
+type PollRepository interface {
+	Create(poll *entity.Poll) error
+	GetByID(id uint) (*entity.Poll, error)
+	GetByPostID(postID uint) (*entity.Poll, error)
+	Vote(pollID, userID uint, optionIDs []uint) error
+	Close(pollID uint, at time.Time) error
+	CountVotes(pollID uint) (map[uint]int64, int64, error)
+	GetVoters(pollID uint) (map[uint][]string, error)
+	GetUserVotes(pollID, userID uint) ([]uint, error)
+}
+
+type pollRepository struct {
+	db *gorm.DB
+}
+
+func NewPollRepository(db *gorm.DB) PollRepository {
+	return &pollRepository{db: db}
+}
+
+func (r *pollRepository) Create(poll *entity.Poll) error {
+	return r.db.Create(poll).Error
+}
+
+func (r *pollRepository) GetByID(id uint) (*entity.Poll, error) {
+	var poll entity.Poll
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&poll, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+func (r *pollRepository) GetByPostID(postID uint) (*entity.Poll, error) {
+	var poll entity.Poll
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("post_id = ?", postID).First(&poll).Error
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+func (r *pollRepository) Vote(pollID, userID uint, optionIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Первичный ключ poll_voters не дает проголосовать дважды даже при параллельных запросах
+		err := tx.Create(&entity.PollVoter{PollID: pollID, UserID: userID}).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyVoted
+		}
+		if err != nil {
+			return err
+		}
+
+		votes := make([]*entity.PollVote, 0, len(optionIDs))
+		for _, optionID := range optionIDs {
+			votes = append(votes, &entity.PollVote{PollID: pollID, UserID: userID, OptionID: optionID})
+		}
+		return tx.Create(&votes).Error
+	})
+}
+
+func (r *pollRepository) Close(pollID uint, at time.Time) error {
+	return r.db.Model(&entity.Poll{}).
+		Where("id = ? AND (closes_at IS NULL OR closes_at > ?)", pollID, at).
+		Update("closes_at", at).Error
+}
+
+func (r *pollRepository) CountVotes(pollID uint) (map[uint]int64, int64, error) {
+	var rows []struct {
+		OptionID uint
+		Count    int64
+	}
+	err := r.db.Model(&entity.PollVote{}).
+		Select("option_id, COUNT(*) AS count").
+		Where("poll_id = ?", pollID).
+		Group("option_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.OptionID] = row.Count
+	}
+
+	var voters int64
+	if err := r.db.Model(&entity.PollVoter{}).Where("poll_id = ?", pollID).Count(&voters).Error; err != nil {
+		return nil, 0, err
+	}
+	return counts, voters, nil
+}
+
+func (r *pollRepository) GetVoters(pollID uint) (map[uint][]string, error) {
+	var rows []struct {
+		OptionID uint
+		Username string
+	}
+	err := r.db.Table("poll_votes").
+		Select("poll_votes.option_id, authors.username").
+		Joins("JOIN authors ON authors.id = poll_votes.user_id").
+		Where("poll_votes.poll_id = ?", pollID).
+		Order("authors.username ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	voters := make(map[uint][]string)
+	for _, row := range rows {
+		voters[row.OptionID] = append(voters[row.OptionID], row.Username)
+	}
+	return voters, nil
+}
+
+func (r *pollRepository) GetUserVotes(pollID, userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&entity.PollVote{}).
+		Where("poll_id = ? AND user_id = ?", pollID, userID).
+		Pluck("option_id", &ids).Error
+	return ids, err
+}
@@ -1,16 +1,32 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
 	"gorm.io/gorm"
 )
 
 type PostRepository interface {
 	Create(post *entity.Post) error
-	GetAll() ([]*entity.Post, error)
+	// GetAll возвращает посты, закрепленные сначала, остальные в порядке sort (entity.Sort*).
+	// С categoryID учитываются и закрепления в категории. Отложенные посты видны только их автору.
+	GetAll(categoryID *uint, sort string, viewerID uint) ([]*entity.Post, error)
 	GetByID(id uint) (*entity.Post, error) // Добавляем новые методы
 	Update(id uint, req *entity.PostRequest) (*entity.Post, error)
 	Delete(id uint) error
+	GetByAuthor(authorID uint, offset, limit int) ([]*entity.Post, error)
+	CountByAuthor(authorID uint) (int64, error)
+	GetByIDs(ids []uint) ([]*entity.Post, error)
+	UpdateFlags(id uint, updates map[string]interface{}) (*entity.Post, error)
+	// ClaimDue публикует до limit отложенных постов, время которых наступило, и возвращает их.
+	// Каждый пост достается ровно одному вызывающему, даже если реплик несколько.
+	ClaimDue(now time.Time, limit int) ([]*entity.Post, error)
+	// GetRecent возвращает последние опубликованные посты категории и/или автора для лент
+	GetRecent(categoryID, authorID *uint, limit int) ([]*entity.Post, error)
+	// AddViews увеличивает счетчики просмотров одним запросом
+	AddViews(counts map[uint]int64) error
 }
 
 type postRepository struct {
@@ -19,7 +35,7 @@ type postRepository struct {
 
 func (r *postRepository) GetByID(id uint) (*entity.Post, error) {
 	var post entity.Post
-	if err := r.db.Preload("Author").First(&post, id).Error; err != nil {
+	if err := r.db.Preload("Author").Preload("Category").Preload("Tags").First(&post, id).Error; err != nil {
 		return nil, err
 	}
 	return &post, nil
@@ -50,13 +66,43 @@ func NewPostRepository(db *gorm.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
+// Create сохраняет пост; о сразу опубликованном посте в той же транзакции пишется событие
 func (r *postRepository) Create(post *entity.Post) error {
-	return r.db.Create(post).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		if post.Scheduled {
+			return nil
+		}
+		return addPostCreatedEvent(tx, post)
+	})
+}
+
+func addPostCreatedEvent(tx *gorm.DB, post *entity.Post) error {
+	event, err := entity.NewPostCreatedEvent(post)
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
 }
 
-func (r *postRepository) GetAll() ([]*entity.Post, error) {
+func (r *postRepository) GetAll(categoryID *uint, sort string, viewerID uint) ([]*entity.Post, error) {
 	var posts []*entity.Post
-	err := r.db.Preload("Author").Find(&posts).Error
+	query := r.db.Scopes(visibleTo(viewerID)).Preload("Author").Preload("Category").Preload("Tags")
+	if categoryID != nil {
+		query = query.
+			Where("posts.category_id = ?", *categoryID).
+			Order("CASE WHEN posts.pin <> '' THEN posts.pinned_at END DESC NULLS LAST")
+	} else {
+		query = query.Order(gorm.Expr("CASE WHEN posts.pin = ? THEN posts.pinned_at END DESC NULLS LAST", entity.PinGlobal))
+	}
+	if sort == entity.SortHot {
+		query = query.
+			Joins("LEFT JOIN post_ranks ON post_ranks.post_id = posts.id").
+			Order("COALESCE(post_ranks.hot_score, 0) DESC")
+	}
+	err := query.Order("posts.created_at DESC").Find(&posts).Error
 	return posts, err
 }
 func (r *postRepository) GetAllWithPagination(offset, limit int) ([]*entity.Post, error) {
@@ -68,3 +114,121 @@ func (r *postRepository) GetAllWithPagination(offset, limit int) ([]*entity.Post
 		Find(&posts).Error
 	return posts, err
 }
+
+func (r *postRepository) GetByAuthor(authorID uint, offset, limit int) ([]*entity.Post, error) {
+	var posts []*entity.Post
+	err := r.db.Scopes(visibleTo(0)).Preload("Author").Preload("Tags").
+		Where("author_id = ?", authorID).
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) CountByAuthor(authorID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Post{}).Scopes(visibleTo(0)).Where("author_id = ?", authorID).Count(&count).Error
+	return count, err
+}
+
+func (r *postRepository) GetByIDs(ids []uint) ([]*entity.Post, error) {
+	var posts []*entity.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) UpdateFlags(id uint, updates map[string]interface{}) (*entity.Post, error) {
+	var post entity.Post
+	if err := r.db.First(&post, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&post).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *postRepository) ClaimDue(now time.Time, limit int) ([]*entity.Post, error) {
+	var posts []*entity.Post
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		// SKIP LOCKED не дает двум репликам забрать один пост, а повторный вызов
+		// не найдет его, потому что флаг снимается в том же запросе.
+		// created_at сдвигается на момент публикации, чтобы пост появился в лентах как новый.
+		err := tx.Raw(`
+			UPDATE posts SET scheduled = false, created_at = publish_at, updated_at = ?
+			WHERE id IN (
+				SELECT id FROM posts
+				WHERE scheduled AND publish_at <= ? AND deleted_at IS NULL
+				ORDER BY publish_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id`,
+			now, now, limit,
+		).Scan(&ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = tx.Preload("Author").Preload("Category").Preload("Tags").
+			Where("id IN ?", ids).
+			Order("publish_at").
+			Find(&posts).Error
+		if err != nil {
+			return err
+		}
+		for _, post := range posts {
+			if err := addPostCreatedEvent(tx, post); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return posts, err
+}
+
+func (r *postRepository) GetRecent(categoryID, authorID *uint, limit int) ([]*entity.Post, error) {
+	var posts []*entity.Post
+	query := r.db.Scopes(visibleTo(0)).Preload("Author").Preload("Category")
+	if categoryID != nil {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+	if authorID != nil {
+		query = query.Where("author_id = ?", *authorID)
+	}
+	err := query.Order("created_at DESC").Limit(limit).Find(&posts).Error
+	return posts, err
+}
+
+func (r *postRepository) AddViews(counts map[uint]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	rows := make([]string, 0, len(counts))
+	args := make([]interface{}, 0, 2*len(counts))
+	for postID, n := range counts {
+		rows = append(rows, "(?::bigint, ?::bigint)")
+		args = append(args, postID, n)
+	}
+	return r.db.Exec(`
+		UPDATE posts SET view_count = posts.view_count + v.n
+		FROM (VALUES `+strings.Join(rows, ", ")+`) AS v(id, n)
+		WHERE posts.id = v.id`,
+		args...,
+	).Error
+}
+
+// visibleTo скрывает отложенные посты от всех, кроме автора
+func visibleTo(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewerID == 0 {
+			return db.Where("posts.scheduled = ?", false)
+		}
+		return db.Where("(posts.scheduled = ? OR posts.author_id = ?)", false, viewerID)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProjectionRepository применяет события auth-service к локальной копии пользователей
+// и их санкций. Каждое событие применяется не больше одного раза, а событие с версией
+// меньше уже примененной пропускается.
+type ProjectionRepository interface {
+	ApplyUser(ctx context.Context, eventID string, user *entity.User) error
+	ApplySanction(ctx context.Context, eventID string, sanction *entity.Sanction) error
+}
+
+type projectionRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectionRepository(db *gorm.DB) ProjectionRepository {
+	return &projectionRepository{db: db}
+}
+
+func (r *projectionRepository) ApplyUser(ctx context.Context, eventID string, user *entity.User) error {
+	return r.once(ctx, eventID, func(tx *gorm.DB) error {
+		// Профиль (имя, аватар и т.д.) ведется здесь и событием не перезаписывается
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"username", "email", "role", "updated_at", "version"}),
+			Where:     newerVersion("authors"),
+		}).Create(user).Error
+	})
+}
+
+func (r *projectionRepository) ApplySanction(ctx context.Context, eventID string, sanction *entity.Sanction) error {
+	return r.once(ctx, eventID, func(tx *gorm.DB) error {
+		// Снятую санкцию запоздавшее событие о выдаче снова не включает
+		updates := append(clause.AssignmentColumns([]string{"reason", "expires_at", "version"}), clause.Assignment{
+			Column: clause.Column{Name: "lifted_at"},
+			Value:  gorm.Expr("COALESCE(forum_user_sanctions.lifted_at, excluded.lifted_at)"),
+		})
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: updates,
+			Where:     newerVersion("forum_user_sanctions"),
+		}).Create(sanction).Error
+	})
+}
+
+// newerVersion пропускает обновление, если в table уже применено более новое событие.
+// События без версии (version 0) применяются как раньше, в порядке поступления.
+func newerVersion(table string) clause.Where {
+	return clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: table + ".version <= excluded.version"},
+	}}
+}
+
+// once выполняет apply в одной транзакции с отметкой события, повтор пропускается
+func (r *projectionRepository) once(ctx context.Context, eventID string, apply func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.ProcessedEvent{
+			EventID:     eventID,
+			ProcessedAt: time.Now(),
+		})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return apply(tx)
+	})
+}
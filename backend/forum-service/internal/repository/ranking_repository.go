@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+// RankingParams - веса активности и скорость затухания для оценки постов
+type RankingParams struct {
+	VoteWeight    float64
+	CommentWeight float64
+	ViewWeight    float64
+	// Gravity - степень, в которую возводится возраст поста в часах: чем больше, тем быстрее посты уходят вниз
+	Gravity float64
+	// TrendingWindow - за какой период учитывается активность в трендах
+	TrendingWindow time.Duration
+}
+
+type RankingRepository interface {
+	// Refresh пересчитывает оценки всех опубликованных постов
+	Refresh(params RankingParams, now time.Time) error
+	GetTrending(offset, limit int) ([]*entity.Post, error)
+}
+
+type rankingRepository struct {
+	db *gorm.DB
+}
+
+func NewRankingRepository(db *gorm.DB) RankingRepository {
+	return &rankingRepository{db: db}
+}
+
+func (r *rankingRepository) Refresh(params RankingParams, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO post_ranks (post_id, hot_score, trending_score, computed_at)
+			SELECT p.id,
+				(p.score * @votes + COALESCE(c.total, 0) * @comments + p.view_count * @views)
+					/ POWER(GREATEST(EXTRACT(EPOCH FROM (@now - p.created_at)) / 3600, 0) + 2, @gravity),
+				COALESCE(v.recent, 0) * @votes + COALESCE(c.recent, 0) * @comments,
+				@now
+			FROM posts p
+			LEFT JOIN (
+				SELECT post_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE created_at >= @since) AS recent
+				FROM comments WHERE deleted_at IS NULL GROUP BY post_id
+			) c ON c.post_id = p.id
+			LEFT JOIN (
+				SELECT post_id, SUM(value) AS recent
+				FROM post_votes WHERE voted_at >= @since GROUP BY post_id
+			) v ON v.post_id = p.id
+			WHERE p.deleted_at IS NULL AND NOT p.scheduled
+			ON CONFLICT (post_id) DO UPDATE SET
+				hot_score = EXCLUDED.hot_score,
+				trending_score = EXCLUDED.trending_score,
+				computed_at = EXCLUDED.computed_at`,
+			map[string]interface{}{
+				"votes":    params.VoteWeight,
+				"comments": params.CommentWeight,
+				"views":    params.ViewWeight,
+				"gravity":  params.Gravity,
+				"now":      now,
+				"since":    now.Add(-params.TrendingWindow),
+			},
+		).Error
+		if err != nil {
+			return err
+		}
+
+		// Оценки удаленных постов больше не нужны
+		return tx.Exec(`DELETE FROM post_ranks WHERE computed_at < ?`, now).Error
+	})
+}
+
+func (r *rankingRepository) GetTrending(offset, limit int) ([]*entity.Post, error) {
+	var posts []*entity.Post
+	err := r.db.Preload("Author").Preload("Category").Preload("Tags").
+		Joins("JOIN post_ranks ON post_ranks.post_id = posts.id").
+		Where("post_ranks.trending_score > 0").
+		Order("post_ranks.trending_score DESC").
+		Order("posts.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostUnread - состояние чтения одного поста
+type PostUnread struct {
+	PostID         uint
+	Unseen         bool
+	UnreadComments int64
+}
+
+type ReadRepository interface {
+	// MarkPostRead сдвигает позицию чтения вперед, но никогда назад
+	MarkPostRead(userID, postID, lastCommentID uint, at time.Time) error
+	MarkCategoryRead(userID, categoryID uint, at time.Time) error
+	GetUnread(userID uint, postIDs []uint) (map[uint]*PostUnread, error)
+	CountUnreadByCategory(userID uint) (map[uint]int64, error)
+	// FirstUnreadComment возвращает первый непрочитанный комментарий и число комментариев перед ним
+	FirstUnreadComment(userID, postID uint) (*uint, int64, error)
+}
+
+type readRepository struct {
+	db *gorm.DB
+}
+
+func NewReadRepository(db *gorm.DB) ReadRepository {
+	return &readRepository{db: db}
+}
+
+func (r *readRepository) MarkPostRead(userID, postID, lastCommentID uint, at time.Time) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_read_comment_id": gorm.Expr("GREATEST(post_reads.last_read_comment_id, EXCLUDED.last_read_comment_id)"),
+			"read_at":              gorm.Expr("EXCLUDED.read_at"),
+		}),
+	}).Create(&entity.PostRead{
+		UserID:            userID,
+		PostID:            postID,
+		LastReadCommentID: lastCommentID,
+		ReadAt:            at,
+	}).Error
+}
+
+func (r *readRepository) MarkCategoryRead(userID, categoryID uint, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "category_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"read_at"}),
+		}).Create(&entity.CategoryRead{
+			UserID:     userID,
+			CategoryID: categoryID,
+			ReadAt:     at,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		// Отметка категории покрывает все существующие посты, отдельные позиции больше не нужны
+		return tx.
+			Where("user_id = ? AND post_id IN (?)", userID,
+				tx.Model(&entity.Post{}).Unscoped().Select("id").Where("category_id = ?", categoryID)).
+			Delete(&entity.PostRead{}).Error
+	})
+}
+
+func (r *readRepository) GetUnread(userID uint, postIDs []uint) (map[uint]*PostUnread, error) {
+	result := make(map[uint]*PostUnread, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+	for _, id := range postIDs {
+		result[id] = &PostUnread{PostID: id}
+	}
+
+	var unseen []uint
+	err := r.db.Raw(`
+		SELECT p.id FROM posts p
+		LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ?
+		LEFT JOIN category_reads cr ON cr.category_id = p.category_id AND cr.user_id = ?
+		WHERE p.id IN ? AND pr.post_id IS NULL AND (cr.read_at IS NULL OR p.created_at > cr.read_at)`,
+		userID, userID, postIDs,
+	).Scan(&unseen).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range unseen {
+		result[id].Unseen = true
+	}
+
+	var counts []struct {
+		PostID uint
+		Count  int64
+	}
+	err = r.db.Raw(`
+		SELECT c.post_id, COUNT(*) AS count FROM comments c
+		JOIN posts p ON p.id = c.post_id
+		LEFT JOIN post_reads pr ON pr.post_id = c.post_id AND pr.user_id = ?
+		LEFT JOIN category_reads cr ON cr.category_id = p.category_id AND cr.user_id = ?
+		WHERE c.post_id IN ? AND c.deleted_at IS NULL
+			AND c.id > COALESCE(pr.last_read_comment_id, 0)
+			AND (cr.read_at IS NULL OR c.created_at > cr.read_at)
+		GROUP BY c.post_id`,
+		userID, userID, postIDs,
+	).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		result[c.PostID].UnreadComments = c.Count
+	}
+	return result, nil
+}
+
+func (r *readRepository) CountUnreadByCategory(userID uint) (map[uint]int64, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int64
+	}
+	err := r.db.Raw(`
+		SELECT p.category_id, COUNT(*) AS count FROM posts p
+		LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ?
+		LEFT JOIN category_reads cr ON cr.category_id = p.category_id AND cr.user_id = ?
+		WHERE p.deleted_at IS NULL AND NOT p.scheduled AND p.category_id IS NOT NULL AND (
+			(pr.post_id IS NULL AND (cr.read_at IS NULL OR p.created_at > cr.read_at))
+			OR EXISTS (
+				SELECT 1 FROM comments c
+				WHERE c.post_id = p.id AND c.deleted_at IS NULL
+					AND c.id > COALESCE(pr.last_read_comment_id, 0)
+					AND (cr.read_at IS NULL OR c.created_at > cr.read_at)
+			)
+		)
+		GROUP BY p.category_id`,
+		userID, userID,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *readRepository) FirstUnreadComment(userID, postID uint) (*uint, int64, error) {
+	var ids []uint
+	err := r.db.Raw(`
+		SELECT c.id FROM comments c
+		JOIN posts p ON p.id = c.post_id
+		LEFT JOIN post_reads pr ON pr.post_id = c.post_id AND pr.user_id = ?
+		LEFT JOIN category_reads cr ON cr.category_id = p.category_id AND cr.user_id = ?
+		WHERE c.post_id = ? AND c.deleted_at IS NULL
+			AND c.id > COALESCE(pr.last_read_comment_id, 0)
+			AND (cr.read_at IS NULL OR c.created_at > cr.read_at)
+		ORDER BY c.id ASC
+		LIMIT 1`,
+		userID, userID, postID,
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := r.db.Model(&entity.Comment{}).Where("post_id = ?", postID)
+	if len(ids) > 0 {
+		query = query.Where("id < ?", ids[0])
+	}
+	var before int64
+	if err := query.Count(&before).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if len(ids) == 0 {
+		return nil, before, nil
+	}
+	return &ids[0], before, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+type SanctionRepository interface {
+	FindActive(userID uint, now time.Time) ([]*entity.Sanction, error)
+}
+
+type sanctionRepository struct {
+	db *gorm.DB
+}
+
+func NewSanctionRepository(db *gorm.DB) SanctionRepository {
+	return &sanctionRepository{db: db}
+}
+
+func (r *sanctionRepository) FindActive(userID uint, now time.Time) ([]*entity.Sanction, error) {
+	var sanctions []*entity.Sanction
+	err := r.db.
+		Where("user_id = ? AND lifted_at IS NULL AND starts_at <= ?", userID, now).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("starts_at DESC").
+		Find(&sanctions).Error
+	return sanctions, err
+}
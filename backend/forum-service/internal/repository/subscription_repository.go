@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SubscriptionRepository interface {
+	// Subscribe создает подписку или обновляет способ доставки существующей
+	Subscribe(sub *entity.Subscription) error
+	// SubscribeIfAbsent создает подписку, не меняя уже существующую
+	SubscribeIfAbsent(sub *entity.Subscription) error
+	Unsubscribe(userID uint, targetType string, targetID uint) error
+	GetSubscribers(targetType string, targetID uint) ([]*entity.Subscription, error)
+	ListByUser(userID uint, targetType string, offset, limit int) ([]*entity.Subscription, error)
+}
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (r *subscriptionRepository) Subscribe(sub *entity.Subscription) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "target_type"}, {Name: "target_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"delivery"}),
+	}).Create(sub).Error
+}
+
+func (r *subscriptionRepository) SubscribeIfAbsent(sub *entity.Subscription) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(sub).Error
+}
+
+func (r *subscriptionRepository) Unsubscribe(userID uint, targetType string, targetID uint) error {
+	res := r.db.
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+		Delete(&entity.Subscription{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) GetSubscribers(targetType string, targetID uint) ([]*entity.Subscription, error) {
+	var subs []*entity.Subscription
+	err := r.db.
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *subscriptionRepository) ListByUser(userID uint, targetType string, offset, limit int) ([]*entity.Subscription, error) {
+	var subs []*entity.Subscription
+	query := r.db.Where("user_id = ?", userID)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&subs).Error
+	return subs, err
+}
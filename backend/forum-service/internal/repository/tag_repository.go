@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	// Resolve возвращает теги с указанными именами, создавая недостающие
+	Resolve(names []string) ([]entity.Tag, error)
+	ReplacePostTags(post *entity.Post, tags []entity.Tag) error
+	GetByName(name string) (*entity.Tag, error)
+	// Search возвращает теги с префиксом prefix: сначала курируемые, затем самые популярные
+	Search(prefix string, limit int) ([]*entity.Tag, error)
+	GetPosts(tagID uint, offset, limit int) ([]*entity.Post, error)
+	CountPosts(tagID uint) (int64, error)
+	Curate(name string) (*entity.Tag, error)
+	Rename(id uint, name string) error
+	// Merge переносит посты тега source на target и удаляет source
+	Merge(sourceID, targetID uint) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Resolve(names []string) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	fresh := make([]entity.Tag, 0, len(names))
+	for _, name := range names {
+		fresh = append(fresh, entity.Tag{Name: name})
+	}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&fresh).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Where("name IN ?", names).Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) ReplacePostTags(post *entity.Post, tags []entity.Tag) error {
+	return r.db.Model(post).Association("Tags").Replace(tags)
+}
+
+func (r *tagRepository) GetByName(name string) (*entity.Tag, error) {
+	var tag entity.Tag
+	if err := r.db.Where("name = ?", name).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) Search(prefix string, limit int) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+	err := r.db.Raw(`
+		SELECT t.*, COUNT(p.id) AS post_count FROM tags t
+		LEFT JOIN post_tags pt ON pt.tag_id = t.id
+		LEFT JOIN posts p ON p.id = pt.post_id AND p.deleted_at IS NULL AND NOT p.scheduled
+		WHERE t.name LIKE ?
+		GROUP BY t.id
+		ORDER BY t.curated DESC, post_count DESC, t.name ASC
+		LIMIT ?`,
+		escapeLike(prefix)+"%", limit,
+	).Scan(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) GetPosts(tagID uint, offset, limit int) ([]*entity.Post, error) {
+	var posts []*entity.Post
+	err := r.db.Scopes(visibleTo(0)).Preload("Author").Preload("Category").Preload("Tags").
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Where("post_tags.tag_id = ?", tagID).
+		Offset(offset).
+		Limit(limit).
+		Order("posts.created_at DESC").
+		Find(&posts).Error
+	return posts, err
+}
+
+func (r *tagRepository) CountPosts(tagID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Post{}).Scopes(visibleTo(0)).
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Where("post_tags.tag_id = ?", tagID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *tagRepository) Curate(name string) (*entity.Tag, error) {
+	tag := entity.Tag{Name: name, Curated: true}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"curated"}),
+	}).Create(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByName(name)
+}
+
+func (r *tagRepository) Rename(id uint, name string) error {
+	return r.db.Model(&entity.Tag{}).Where("id = ?", id).Update("name", name).Error
+}
+
+func (r *tagRepository) Merge(sourceID, targetID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Посты, у которых уже есть оба тега, сохраняют одну связь
+		err := tx.Exec(`
+			INSERT INTO post_tags (post_id, tag_id)
+			SELECT post_id, ? FROM post_tags WHERE tag_id = ?
+			ON CONFLICT DO NOTHING`,
+			targetID, sourceID,
+		).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM post_tags WHERE tag_id = ?", sourceID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Tag{}, sourceID).Error
+	})
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+// ErrPollConflict - у обоих объединяемых постов есть опрос, а у поста он может быть только один
+var ErrPollConflict = errors.New("both posts have a poll")
+
+// ThreadRepository выполняет перестройку обсуждений. Каждая операция идет
+// в одной транзакции вместе с записью в журнал модерации.
+type ThreadRepository interface {
+	Move(postID, categoryID uint, entry *entity.ModerationLog) error
+	// Merge переносит комментарии, голоса, опрос, упоминания, подписки, закладки и теги
+	// source в target, удаляет source и оставляет перенаправление со старого ID
+	Merge(sourceID, targetID uint, entry *entity.ModerationLog) error
+	// Split создает post из первого комментария и переносит в него остальные
+	Split(sourceID uint, commentIDs []uint, post *entity.Post, entry *entity.ModerationLog) error
+	GetRedirect(oldPostID uint) (uint, error)
+	GetLog(offset, limit int) ([]*entity.ModerationLog, error)
+}
+
+type threadRepository struct {
+	db *gorm.DB
+}
+
+func NewThreadRepository(db *gorm.DB) ThreadRepository {
+	return &threadRepository{db: db}
+}
+
+func (r *threadRepository) Move(postID, categoryID uint, entry *entity.ModerationLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Post{}).Where("id = ?", postID).Update("category_id", categoryID).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *threadRepository) Merge(sourceID, targetID uint, entry *entity.ModerationLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		steps := []struct {
+			sql  string
+			args []interface{}
+		}{
+			{`UPDATE comments SET post_id = ? WHERE post_id = ?`, []interface{}{targetID, sourceID}},
+			{`INSERT INTO subscriptions (user_id, target_type, target_id, delivery, created_at)
+				SELECT user_id, target_type, ?, delivery, created_at FROM subscriptions
+				WHERE target_type = ? AND target_id = ?
+				ON CONFLICT DO NOTHING`, []interface{}{targetID, entity.SubscriptionPost, sourceID}},
+			{`DELETE FROM subscriptions WHERE target_type = ? AND target_id = ?`, []interface{}{entity.SubscriptionPost, sourceID}},
+			{`UPDATE bookmarks SET post_id = ? WHERE post_id = ?
+				AND NOT EXISTS (SELECT 1 FROM bookmarks b WHERE b.user_id = bookmarks.user_id AND b.post_id = ?)`,
+				[]interface{}{targetID, sourceID, targetID}},
+			{`DELETE FROM bookmarks WHERE post_id = ?`, []interface{}{sourceID}},
+			{`INSERT INTO post_tags (post_id, tag_id)
+				SELECT ?, tag_id FROM post_tags WHERE post_id = ?
+				ON CONFLICT DO NOTHING`, []interface{}{targetID, sourceID}},
+			{`DELETE FROM post_reads WHERE post_id = ?`, []interface{}{sourceID}},
+			// Пользователь, голосовавший за оба поста, сохраняет голос за target
+			{`UPDATE post_votes SET post_id = ? WHERE post_id = ?
+				AND NOT EXISTS (SELECT 1 FROM post_votes v WHERE v.user_id = post_votes.user_id AND v.post_id = ?)`,
+				[]interface{}{targetID, sourceID, targetID}},
+			{`DELETE FROM post_votes WHERE post_id = ?`, []interface{}{sourceID}},
+			{`UPDATE posts SET score = (SELECT COALESCE(SUM(value), 0) FROM post_votes WHERE post_id = ?) WHERE id = ?`,
+				[]interface{}{targetID, targetID}},
+			{`INSERT INTO post_mentions (post_id, user_id, username, created_at)
+				SELECT ?, user_id, username, created_at FROM post_mentions WHERE post_id = ?
+				ON CONFLICT DO NOTHING`, []interface{}{targetID, sourceID}},
+			{`DELETE FROM post_mentions WHERE post_id = ?`, []interface{}{sourceID}},
+			// Старые перенаправления на source теперь ведут сразу в target
+			{`UPDATE post_redirects SET new_post_id = ? WHERE new_post_id = ?`, []interface{}{targetID, sourceID}},
+		}
+		for _, step := range steps {
+			if err := tx.Exec(step.sql, step.args...).Error; err != nil {
+				return err
+			}
+		}
+
+		// Опрос переходит вместе с голосами: они ссылаются на опрос, а не на пост
+		var polls int64
+		if err := tx.Model(&entity.Poll{}).Where("post_id = ?", targetID).Count(&polls).Error; err != nil {
+			return err
+		}
+		moved := tx.Model(&entity.Poll{}).Where("post_id = ?", sourceID).Update("post_id", targetID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		if polls > 0 && moved.RowsAffected > 0 {
+			return ErrPollConflict
+		}
+
+		if err := tx.Create(&entity.PostRedirect{OldPostID: sourceID, NewPostID: targetID}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity.Post{}, sourceID).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *threadRepository) Split(sourceID uint, commentIDs []uint, post *entity.Post, entry *entity.ModerationLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var comments []*entity.Comment
+		err := tx.Where("id IN ? AND post_id = ?", commentIDs, sourceID).
+			Order("id ASC").
+			Find(&comments).Error
+		if err != nil {
+			return err
+		}
+		if len(comments) != len(commentIDs) {
+			return fmt.Errorf("%w: some comments do not belong to post %d", gorm.ErrRecordNotFound, sourceID)
+		}
+
+		first := comments[0]
+		post.AuthorID = first.AuthorID
+		post.Content = first.Content
+		post.CreatedAt = first.CreatedAt
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(first).Error; err != nil {
+			return err
+		}
+		if len(comments) > 1 {
+			err := tx.Model(&entity.Comment{}).
+				Where("id IN ?", commentIDs).
+				Where("id <> ?", first.ID).
+				Update("post_id", post.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		entry.TargetID = &post.ID
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *threadRepository) GetRedirect(oldPostID uint) (uint, error) {
+	var redirect entity.PostRedirect
+	if err := r.db.First(&redirect, "old_post_id = ?", oldPostID).Error; err != nil {
+		return 0, err
+	}
+	return redirect.NewPostID, nil
+}
+
+func (r *threadRepository) GetLog(offset, limit int) ([]*entity.ModerationLog, error) {
+	var entries []*entity.ModerationLog
+	err := r.db.Order("created_at DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, err
+}
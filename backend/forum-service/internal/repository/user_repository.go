@@ -6,9 +6,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// UserRepository читает проекцию пользователей auth-service. Записывает в нее
+// только ProjectionRepository, здесь меняется лишь профиль.
 type UserRepository interface {
-	Create(user *entity.User) error
 	GetByUsername(username string) (*entity.User, error)
+	GetByID(id uint) (*entity.User, error)
+	UpdateProfile(id uint, updates map[string]interface{}) (*entity.User, error)
 }
 
 type userRepository struct {
@@ -19,10 +22,6 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *entity.User) error {
-	return r.db.Create(user).Error
-}
-
 func (r *userRepository) GetByUsername(username string) (*entity.User, error) {
 	var user entity.User
 	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
@@ -30,3 +29,24 @@ func (r *userRepository) GetByUsername(username string) (*entity.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepository) GetByID(id uint) (*entity.User, error) {
+	var user entity.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) UpdateProfile(id uint, updates map[string]interface{}) (*entity.User, error) {
+	var user entity.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	if len(updates) > 0 {
+		if err := r.db.Model(&user).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &user, nil
+}
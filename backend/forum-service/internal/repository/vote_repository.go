@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoteRepository interface {
+	// Vote сохраняет голос и возвращает новый счет поста и признак первого голоса пользователя
+	Vote(postID, userID uint, value int, at time.Time) (int64, bool, error)
+	Unvote(postID, userID uint) (int64, error)
+}
+
+type voteRepository struct {
+	db *gorm.DB
+}
+
+func NewVoteRepository(db *gorm.DB) VoteRepository {
+	return &voteRepository{db: db}
+}
+
+func (r *voteRepository) Vote(postID, userID uint, value int, at time.Time) (int64, bool, error) {
+	var score int64
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing entity.PostVote
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("post_id = ? AND user_id = ?", postID, userID).
+			First(&existing).Error
+		delta := value
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+		case err != nil:
+			return err
+		default:
+			delta -= existing.Value
+		}
+
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "post_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "voted_at"}),
+		}).Create(&entity.PostVote{PostID: postID, UserID: userID, Value: value, VotedAt: at}).Error
+		if err != nil {
+			return err
+		}
+		score, err = addScore(tx, postID, delta)
+		return err
+	})
+	return score, created, err
+}
+
+func (r *voteRepository) Unvote(postID, userID uint) (int64, error) {
+	var score int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var removed []entity.PostVote
+		err := tx.Clauses(clause.Returning{}).
+			Where("post_id = ? AND user_id = ?", postID, userID).
+			Delete(&removed).Error
+		if err != nil {
+			return err
+		}
+		delta := 0
+		for _, vote := range removed {
+			delta -= vote.Value
+		}
+		score, err = addScore(tx, postID, delta)
+		return err
+	})
+	return score, err
+}
+
+// addScore меняет денормализованный счет поста и возвращает новое значение
+func addScore(tx *gorm.DB, postID uint, delta int) (int64, error) {
+	var post entity.Post
+	err := tx.Model(&post).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "score"}}}).
+		Where("id = ?", postID).
+		UpdateColumn("score", gorm.Expr("score + ?", delta)).Error
+	return post.Score, err
+}
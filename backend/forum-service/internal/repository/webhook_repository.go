@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	Create(webhook *entity.Webhook) error
+	Update(webhook *entity.Webhook) error
+	Delete(id uint) error
+	GetByID(id uint) (*entity.Webhook, error)
+	List() ([]*entity.Webhook, error)
+	ListActive() ([]*entity.Webhook, error)
+
+	CreateDeliveries(deliveries []*entity.WebhookDelivery) error
+	// ClaimDue забирает до limit доставок, время которых наступило, и откладывает их на lease,
+	// чтобы другая реплика не отправила их параллельно
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error)
+	SaveDelivery(delivery *entity.WebhookDelivery) error
+	GetDelivery(webhookID, id uint) (*entity.WebhookDelivery, error)
+	ListDeliveries(webhookID uint, offset, limit int) ([]*entity.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(webhook *entity.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *webhookRepository) Update(webhook *entity.Webhook) error {
+	return r.db.Select("URL", "Secret", "Events", "Active").Updates(webhook).Error
+}
+
+func (r *webhookRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entity.Webhook{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepository) GetByID(id uint) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+	if err := r.db.First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) List() ([]*entity.Webhook, error) {
+	var webhooks []*entity.Webhook
+	err := r.db.Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) ListActive() ([]*entity.Webhook, error) {
+	var webhooks []*entity.Webhook
+	err := r.db.Where("active = ?", true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) CreateDeliveries(deliveries []*entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+func (r *webhookRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	var ids []uint
+	err := r.db.Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		now.Add(lease), entity.DeliveryPending, now, limit,
+	).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var deliveries []*entity.WebhookDelivery
+	err = r.db.Preload("Webhook").Where("id IN ?", ids).Order("id ASC").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) SaveDelivery(delivery *entity.WebhookDelivery) error {
+	return r.db.Model(delivery).
+		Select("Status", "Attempts", "NextAttemptAt", "LastStatusCode", "LastError", "DeliveredAt").
+		Updates(delivery).Error
+}
+
+func (r *webhookRepository) GetDelivery(webhookID, id uint) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	if err := r.db.Where("webhook_id = ?", webhookID).First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(webhookID uint, offset, limit int) ([]*entity.WebhookDelivery, error) {
+	var deliveries []*entity.WebhookDelivery
+	err := r.db.Where("webhook_id = ?", webhookID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
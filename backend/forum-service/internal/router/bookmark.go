@@ -0,0 +1,111 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func addBookmarkHandler(ctrl controller.BookmarkController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid post ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var req entity.BookmarkRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "invalid request body",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		bookmark, err := ctrl.AddBookmark(userID, uint(postID), &req)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to save bookmark",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, bookmark)
+	}
+}
+
+func removeBookmarkHandler(ctrl controller.BookmarkController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid post ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if err := ctrl.RemoveBookmark(userID, uint(postID)); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "bookmark not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to remove bookmark",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func getBookmarksHandler(ctrl controller.BookmarkController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		page, limit := parsePagination(c)
+		bookmarks, total, err := ctrl.GetBookmarks(userID, c.Query("folder"), page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get bookmarks",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"bookmarks": bookmarks,
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func getCategoriesHandler(ctrl controller.CategoryController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		categories, err := ctrl.GetCategories(c.GetUint("userID"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get categories",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, categories)
+	}
+}
+
+func createCategoryHandler(ctrl controller.CategoryController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.CategoryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		category, err := ctrl.CreateCategory(&req)
+		if err != nil {
+			if errors.Is(err, controller.ErrValidation) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				c.JSON(http.StatusConflict, gin.H{"error": "category slug already exists"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to create category",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusCreated, category)
+	}
+}
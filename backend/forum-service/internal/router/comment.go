@@ -0,0 +1,96 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func getCommentsHandler(ctrl controller.CommentController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid post ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		page, limit := parsePagination(c)
+		comments, err := ctrl.GetComments(uint(postID), page, limit, c.GetUint("userID"))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get comments",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"comments": comments,
+			"page":     page,
+			"limit":    limit,
+		})
+	}
+}
+
+func createCommentHandler(ctrl controller.CommentController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid post ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var req entity.CommentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		authorID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		comment, err := ctrl.CreateComment(uint(postID), &req, authorID, c.GetString("userRole"))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+				return
+			}
+			if errors.Is(err, controller.ErrLocked) {
+				c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, controller.ErrValidation) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to create comment",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusCreated, comment)
+	}
+}
@@ -0,0 +1,220 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func getDraftsHandler(ctrl controller.DraftController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		page, limit := parsePagination(c)
+		drafts, err := ctrl.GetDrafts(userID, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get drafts",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"drafts": drafts,
+			"page":   page,
+			"limit":  limit,
+		})
+	}
+}
+
+func getDraftHandler(ctrl controller.DraftController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, id, ok := draftParams(c)
+		if !ok {
+			return
+		}
+
+		draft, err := ctrl.GetDraft(userID, id)
+		if err != nil {
+			respondDraftError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, draft)
+	}
+}
+
+func createDraftHandler(ctrl controller.DraftController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var req entity.DraftRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		draft, err := ctrl.CreateDraft(userID, &req)
+		if err != nil {
+			respondDraftError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, draft)
+	}
+}
+
+// saveDraftHandler - автосохранение: клиент периодически отправляет текущее состояние целиком
+func saveDraftHandler(ctrl controller.DraftController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, id, ok := draftParams(c)
+		if !ok {
+			return
+		}
+
+		var req entity.DraftRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		draft, err := ctrl.SaveDraft(userID, id, &req)
+		if err != nil {
+			respondDraftError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, draft)
+	}
+}
+
+func deleteDraftHandler(ctrl controller.DraftController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, id, ok := draftParams(c)
+		if !ok {
+			return
+		}
+
+		if err := ctrl.DeleteDraft(userID, id); err != nil {
+			respondDraftError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// publishDraftHandler превращает черновик в пост или ответ, проверяя его так же,
+// как createPostHandler и createCommentHandler проверяют тело запроса
+func publishDraftHandler(
+	draftCtrl controller.DraftController,
+	postCtrl controller.PostController,
+	commentCtrl controller.CommentController,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, id, ok := draftParams(c)
+		if !ok {
+			return
+		}
+
+		draft, err := draftCtrl.GetDraft(userID, id)
+		if err != nil {
+			respondDraftError(c, err)
+			return
+		}
+
+		var published interface{}
+		if draft.ReplyToPostID != nil {
+			req := entity.CommentRequest{Content: draft.Content}
+			if err := binding.Validator.ValidateStruct(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
+			published, err = commentCtrl.CreateComment(*draft.ReplyToPostID, &req, userID, c.GetString("userRole"))
+		} else {
+			req := entity.PostRequest{
+				Title:      draft.Title,
+				Content:    draft.Content,
+				CategoryID: draft.CategoryID,
+			}
+			if err := binding.Validator.ValidateStruct(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
+			published, err = postCtrl.CreatePost(&req, userID)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "reply target not found"})
+			case errors.Is(err, controller.ErrLocked):
+				c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+			case errors.Is(err, controller.ErrValidation):
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   "failed to publish draft",
+					"details": err.Error(),
+				})
+			}
+			return
+		}
+
+		if err := draftCtrl.DeleteDraft(userID, id); err != nil {
+			// Публикация уже состоялась, оставшийся черновик пользователь удалит сам
+			c.Error(err)
+		}
+		c.JSON(http.StatusCreated, published)
+	}
+}
+
+func draftParams(c *gin.Context) (uint, uint, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid draft ID",
+			"details": err.Error(),
+		})
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondDraftError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "draft not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "draft request failed",
+		"details": err.Error(),
+	})
+}
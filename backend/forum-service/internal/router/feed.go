@@ -0,0 +1,114 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/feed"
+	"gorm.io/gorm"
+)
+
+func postsFeedHandler(ctrl controller.FeedController, format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		f, err := ctrl.PostsFeed()
+		writeFeed(c, f, format, err)
+	}
+}
+
+// categoryFeedHandler отдает /feeds/categories/<slug>.atom и /feeds/categories/<slug>.rss
+func categoryFeedHandler(ctrl controller.FeedController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug, format, ok := feedFile(c)
+		if !ok {
+			return
+		}
+		f, err := ctrl.CategoryFeed(slug)
+		writeFeed(c, f, format, err)
+	}
+}
+
+// authorFeedHandler отдает /feeds/users/<username>.atom и /feeds/users/<username>.rss
+func authorFeedHandler(ctrl controller.FeedController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username, format, ok := feedFile(c)
+		if !ok {
+			return
+		}
+		f, err := ctrl.AuthorFeed(username)
+		writeFeed(c, f, format, err)
+	}
+}
+
+func feedFile(c *gin.Context) (string, string, bool) {
+	file := c.Param("file")
+	ext := path.Ext(file)
+	format := strings.TrimPrefix(ext, ".")
+	name := strings.TrimSuffix(file, ext)
+	if name == "" || (format != feed.FormatAtom && format != feed.FormatRSS) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+		return "", "", false
+	}
+	return name, format, true
+}
+
+// writeFeed отвечает 304, если у читалки уже есть актуальная версия ленты
+func writeFeed(c *gin.Context, f *feed.Feed, format string, err error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to build feed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	f.SelfLink = scheme + "://" + c.Request.Host + c.Request.URL.Path
+
+	etag := f.ETag(format)
+	lastModified := f.Updated.UTC().Truncate(time.Second)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=300")
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if etagMatches(match, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	body, contentType, err := f.Encode(format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to encode feed",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
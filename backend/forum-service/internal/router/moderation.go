@@ -0,0 +1,103 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func pinPostHandler(ctrl controller.PostController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, id, ok := moderationParams(c)
+		if !ok {
+			return
+		}
+
+		var req entity.PinRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		post, err := ctrl.PinPost(id, req.Scope, actorID)
+		if err != nil {
+			respondModerationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, post)
+	}
+}
+
+func unpinPostHandler(ctrl controller.PostController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, id, ok := moderationParams(c)
+		if !ok {
+			return
+		}
+
+		post, err := ctrl.UnpinPost(id, actorID)
+		if err != nil {
+			respondModerationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, post)
+	}
+}
+
+// setPostFlagHandler включает или выключает флаг поста (закрытие, объявление)
+func setPostFlagHandler(set func(id uint, value bool, actorID uint) (*entity.Post, error), value bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, id, ok := moderationParams(c)
+		if !ok {
+			return
+		}
+
+		post, err := set(id, value, actorID)
+		if err != nil {
+			respondModerationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, post)
+	}
+}
+
+func moderationParams(c *gin.Context) (uint, uint, bool) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid post ID",
+			"details": err.Error(),
+		})
+		return 0, 0, false
+	}
+	return actorID, uint(id), true
+}
+
+func respondModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "moderation action failed",
+			"details": err.Error(),
+		})
+	}
+}
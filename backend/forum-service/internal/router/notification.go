@@ -0,0 +1,192 @@
+package router
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/lifecycle"
+	"gorm.io/gorm"
+)
+
+// sseKeepAlive - интервал пустых событий, не дающий прокси закрыть соединение
+const sseKeepAlive = 25 * time.Second
+
+func getNotificationsHandler(ctrl controller.NotificationController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		page, limit := parsePagination(c)
+		unreadOnly := c.Query("unread") == "true"
+
+		notifications, unread, err := ctrl.List(userID, unreadOnly, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get notifications",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"notifications": notifications,
+			"unread_count":  unread,
+			"page":          page,
+			"limit":         limit,
+		})
+	}
+}
+
+func markNotificationReadHandler(ctrl controller.NotificationController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid notification ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if err := ctrl.MarkRead(userID, uint(id)); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to mark notification as read",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func markAllNotificationsReadHandler(ctrl controller.NotificationController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		if err := ctrl.MarkAllRead(userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to mark notifications as read",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func getNotificationPreferencesHandler(ctrl controller.NotificationController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		prefs, err := ctrl.GetPreferences(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get preferences",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, prefs)
+	}
+}
+
+func updateNotificationPreferencesHandler(ctrl controller.NotificationController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var req map[string]bool
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		prefs, err := ctrl.UpdatePreferences(userID, req)
+		if err != nil {
+			if errors.Is(err, controller.ErrValidation) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to update preferences",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, prefs)
+	}
+}
+
+// notificationStreamHandler доставляет уведомления в реальном времени через Server-Sent Events
+func notificationStreamHandler(ctrl controller.NotificationController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		notifications, unsubscribe := ctrl.Subscribe(userID)
+		defer unsubscribe()
+
+		// Поток живет дольше WriteTimeout сервера
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+
+		keepAlive := time.NewTicker(sseKeepAlive)
+		defer keepAlive.Stop()
+
+		stopping := lifecycle.Stopping(c.Request.Context())
+		c.SSEvent("ready", gin.H{"user_id": userID})
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case <-c.Request.Context().Done():
+				return false
+			case <-stopping:
+				// Сервер останавливается; EventSource переподключится к другой реплике
+				return false
+			case n, ok := <-notifications:
+				if !ok {
+					return false
+				}
+				c.SSEvent("notification", n)
+				return true
+			case <-keepAlive.C:
+				c.SSEvent("ping", time.Now().Unix())
+				return true
+			}
+		})
+	}
+}
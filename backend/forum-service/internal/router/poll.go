@@ -0,0 +1,147 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+func createPollHandler(ctrl controller.PollController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid post ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var req entity.PollRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		poll, err := ctrl.CreatePoll(uint(postID), userID, &req)
+		if err != nil {
+			respondPollError(c, "post", err)
+			return
+		}
+		c.JSON(http.StatusCreated, poll)
+	}
+}
+
+func getPollHandler(ctrl controller.PollController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pollID, ok := pollIDParam(c)
+		if !ok {
+			return
+		}
+
+		poll, err := ctrl.GetResults(pollID, c.GetUint("userID"))
+		if err != nil {
+			respondPollError(c, "poll", err)
+			return
+		}
+		c.JSON(http.StatusOK, poll)
+	}
+}
+
+func votePollHandler(ctrl controller.PollController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		pollID, ok := pollIDParam(c)
+		if !ok {
+			return
+		}
+
+		var req entity.VoteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		poll, err := ctrl.Vote(pollID, userID, &req)
+		if err != nil {
+			respondPollError(c, "poll", err)
+			return
+		}
+		c.JSON(http.StatusOK, poll)
+	}
+}
+
+func closePollHandler(ctrl controller.PollController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		pollID, ok := pollIDParam(c)
+		if !ok {
+			return
+		}
+
+		poll, err := ctrl.ClosePoll(pollID, userID)
+		if err != nil {
+			respondPollError(c, "poll", err)
+			return
+		}
+		c.JSON(http.StatusOK, poll)
+	}
+}
+
+func pollIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid poll ID",
+			"details": err.Error(),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondPollError(c *gin.Context, target string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": target + " not found"})
+	case errors.Is(err, repository.ErrAlreadyVoted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, controller.ErrPollClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, controller.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "poll request failed",
+			"details": err.Error(),
+		})
+	}
+}
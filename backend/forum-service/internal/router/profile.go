@@ -0,0 +1,184 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parsePagination читает page и limit из query-параметров
+func parsePagination(c *gin.Context) (page, limit int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
+
+// currentUserID достает ID пользователя, установленный AuthMiddleware
+func currentUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return 0, false
+	}
+	userID, ok := value.(uint)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID format"})
+		return 0, false
+	}
+	return userID, true
+}
+
+func getProfileHandler(ctrl controller.ProfileController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		profile, err := ctrl.GetProfile(c.Param("username"))
+		if err != nil {
+			respondProfileError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+func getUserPostsHandler(ctrl controller.ProfileController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, limit := parsePagination(c)
+		posts, err := ctrl.GetUserPosts(c.Param("username"), page, limit)
+		if err != nil {
+			respondProfileError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"posts": posts,
+			"page":  page,
+			"limit": limit,
+		})
+	}
+}
+
+func getMeHandler(ctrl controller.ProfileController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		profile, err := ctrl.GetOwnProfile(userID)
+		if err != nil {
+			respondProfileError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+func updateMeHandler(ctrl controller.ProfileController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var req entity.ProfileUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "validation error",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		profile, err := ctrl.UpdateProfile(userID, &req)
+		if err != nil {
+			respondProfileError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+func uploadAvatarHandler(ctrl controller.ProfileController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, controller.MaxAvatarSize+1<<20)
+		file, err := c.FormFile("avatar")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "avatar file is required",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		profile, err := ctrl.UpdateAvatar(userID, file)
+		if err != nil {
+			respondProfileError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+func getAvatarHandler(ctrl controller.ProfileController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid user ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		avatar, err := ctrl.GetAvatar(uint(userID))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "avatar not found"})
+				return
+			}
+			respondProfileError(c, err)
+			return
+		}
+		// Адрес аватара меняется при каждой загрузке, поэтому кэш может жить долго
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Data(http.StatusOK, avatar.ContentType, avatar.Data)
+	}
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "profile request failed",
+			"details": err.Error(),
+		})
+	}
+}
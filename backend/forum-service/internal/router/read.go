@@ -0,0 +1,111 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"gorm.io/gorm"
+)
+
+type markPostReadRequest struct {
+	LastCommentID uint `json:"last_comment_id"`
+}
+
+func markPostReadHandler(ctrl controller.ReadController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid post ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var req markPostReadRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "invalid request body",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		if err := ctrl.MarkPostRead(userID, uint(postID), req.LastCommentID); err != nil {
+			respondReadError(c, "post", err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func markCategoryReadHandler(ctrl controller.ReadController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid category ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if err := ctrl.MarkCategoryRead(userID, uint(categoryID)); err != nil {
+			respondReadError(c, "category", err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func firstUnreadHandler(ctrl controller.ReadController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid post ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		_, limit := parsePagination(c)
+		position, err := ctrl.FirstUnread(userID, uint(postID), limit)
+		if err != nil {
+			respondReadError(c, "post", err)
+			return
+		}
+		c.JSON(http.StatusOK, position)
+	}
+}
+
+func respondReadError(c *gin.Context, target string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": target + " not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "read tracking failed",
+		"details": err.Error(),
+	})
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -12,21 +13,48 @@ import (
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/delivery"
 	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/feed"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/metrics"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/service"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/health"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
 )
 
 // SetupRouter создает и настраивает маршруты приложения
 func SetupRouter(
 	postCtrl controller.PostController,
+	profileCtrl controller.ProfileController,
+	commentCtrl controller.CommentController,
+	notificationCtrl controller.NotificationController,
+	categoryCtrl controller.CategoryController,
+	subscriptionCtrl controller.SubscriptionController,
+	readCtrl controller.ReadController,
+	bookmarkCtrl controller.BookmarkController,
+	draftCtrl controller.DraftController,
+	pollCtrl controller.PollController,
+	tagCtrl controller.TagController,
+	threadCtrl controller.ThreadController,
+	voteCtrl controller.VoteController,
+	feedCtrl controller.FeedController,
+	webhookCtrl controller.WebhookController,
+	views service.ViewCounter,
 	authMiddleware *delivery.AuthMiddleware,
+	writeLimiter *delivery.RateLimiter,
+	allowedOrigins []string,
+	probes *health.Health,
 ) *gin.Engine {
 	router := gin.Default()
+	// Спан запроса продолжает трассу из заголовка traceparent
+	router.Use(otelgin.Middleware("forum-service", otelgin.WithFilter(tracing.Traced)))
+	router.Use(metrics.HTTP())
 
 	// Настройка CORS
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowOrigins:     allowedOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "traceparent", "tracestate"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -43,24 +71,114 @@ func SetupRouter(
 
 	// Группа публичных маршрутов
 	public := router.Group("/api/v1")
+	public.Use(authMiddleware.Optional())
 	{
 		public.GET("/posts", getAllPostsHandler(postCtrl))
-		public.GET("/posts/:id", getPostByIDHandler(postCtrl))
+		public.GET("/posts/trending", getTrendingHandler(postCtrl))
+		public.GET("/posts/:id", getPostByIDHandler(postCtrl, threadCtrl, views))
+		public.GET("/posts/:id/comments", getCommentsHandler(commentCtrl))
+		public.GET("/categories", getCategoriesHandler(categoryCtrl))
+		public.GET("/polls/:id", getPollHandler(pollCtrl))
+		public.GET("/tags", searchTagsHandler(tagCtrl))
+		public.GET("/tags/:tag/posts", getTagPostsHandler(tagCtrl))
+		public.GET("/users/:username", getProfileHandler(profileCtrl))
+		public.GET("/users/:username/posts", getUserPostsHandler(profileCtrl))
+		public.GET("/avatars/:id", getAvatarHandler(profileCtrl))
+	}
+
+	// Ленты RSS и Atom
+	feeds := router.Group("/feeds")
+	{
+		feeds.GET("/posts.atom", postsFeedHandler(feedCtrl, feed.FormatAtom))
+		feeds.GET("/posts.rss", postsFeedHandler(feedCtrl, feed.FormatRSS))
+		feeds.GET("/categories/:file", categoryFeedHandler(feedCtrl))
+		feeds.GET("/users/:file", authorFeedHandler(feedCtrl))
+	}
+
+	// Маршруты для любого вошедшего пользователя, включая лишенных права публикации
+	authenticated := router.Group("/api/v1")
+	authenticated.Use(authMiddleware.Handler())
+	{
+		authenticated.GET("/me", getMeHandler(profileCtrl))
+		authenticated.GET("/notifications", getNotificationsHandler(notificationCtrl))
+		authenticated.GET("/notifications/stream", notificationStreamHandler(notificationCtrl))
+		authenticated.POST("/notifications/read-all", markAllNotificationsReadHandler(notificationCtrl))
+		authenticated.POST("/notifications/:id/read", markNotificationReadHandler(notificationCtrl))
+		authenticated.GET("/notifications/preferences", getNotificationPreferencesHandler(notificationCtrl))
+		authenticated.PUT("/notifications/preferences", updateNotificationPreferencesHandler(notificationCtrl))
+		authenticated.GET("/me/watched", getWatchedHandler(subscriptionCtrl))
+		authenticated.POST("/posts/:id/read", markPostReadHandler(readCtrl))
+		authenticated.GET("/posts/:id/first-unread", firstUnreadHandler(readCtrl))
+		authenticated.POST("/categories/:id/read", markCategoryReadHandler(readCtrl))
+		authenticated.GET("/me/bookmarks", getBookmarksHandler(bookmarkCtrl))
+		authenticated.PUT("/posts/:id/bookmark", addBookmarkHandler(bookmarkCtrl))
+		authenticated.DELETE("/posts/:id/bookmark", removeBookmarkHandler(bookmarkCtrl))
+		authenticated.GET("/drafts", getDraftsHandler(draftCtrl))
+		authenticated.POST("/drafts", createDraftHandler(draftCtrl))
+		authenticated.GET("/drafts/:id", getDraftHandler(draftCtrl))
+		authenticated.PUT("/drafts/:id", saveDraftHandler(draftCtrl))
+		authenticated.DELETE("/drafts/:id", deleteDraftHandler(draftCtrl))
+		authenticated.POST("/posts/:id/subscription", subscribeHandler(subscriptionCtrl, entity.SubscriptionPost))
+		authenticated.DELETE("/posts/:id/subscription", unsubscribeHandler(subscriptionCtrl, entity.SubscriptionPost))
+		authenticated.POST("/categories/:id/subscription", subscribeHandler(subscriptionCtrl, entity.SubscriptionCategory))
+		authenticated.DELETE("/categories/:id/subscription", unsubscribeHandler(subscriptionCtrl, entity.SubscriptionCategory))
+	}
+
+	// Действия модераторов
+	moderation := router.Group("/api/v1")
+	moderation.Use(authMiddleware.Handler(), authMiddleware.RequireRole(entity.RoleModerator, entity.RoleAdmin))
+	{
+		moderation.POST("/categories", createCategoryHandler(categoryCtrl))
+		moderation.POST("/tags", curateTagHandler(tagCtrl))
+		moderation.PATCH("/tags/:tag", renameTagHandler(tagCtrl))
+		moderation.POST("/tags/:tag/merge", mergeTagsHandler(tagCtrl))
+		moderation.POST("/posts/:id/move", movePostHandler(threadCtrl))
+		moderation.POST("/posts/:id/merge", mergePostsHandler(threadCtrl))
+		moderation.POST("/posts/:id/split", splitPostHandler(threadCtrl))
+		moderation.GET("/moderation/log", getModerationLogHandler(threadCtrl))
+		moderation.PUT("/posts/:id/pin", pinPostHandler(postCtrl))
+		moderation.DELETE("/posts/:id/pin", unpinPostHandler(postCtrl))
+		moderation.PUT("/posts/:id/lock", setPostFlagHandler(postCtrl.SetLocked, true))
+		moderation.DELETE("/posts/:id/lock", setPostFlagHandler(postCtrl.SetLocked, false))
+		moderation.PUT("/posts/:id/announcement", setPostFlagHandler(postCtrl.SetAnnouncement, true))
+		moderation.DELETE("/posts/:id/announcement", setPostFlagHandler(postCtrl.SetAnnouncement, false))
+	}
+
+	// Администрирование интеграций
+	admin := router.Group("/api/v1/admin")
+	admin.Use(authMiddleware.Handler(), authMiddleware.RequireRole(entity.RoleAdmin))
+	{
+		admin.GET("/webhooks", getWebhooksHandler(webhookCtrl))
+		admin.POST("/webhooks", createWebhookHandler(webhookCtrl))
+		admin.PUT("/webhooks/:id", updateWebhookHandler(webhookCtrl))
+		admin.DELETE("/webhooks/:id", deleteWebhookHandler(webhookCtrl))
+		admin.GET("/webhooks/:id/deliveries", getWebhookDeliveriesHandler(webhookCtrl))
+		admin.POST("/webhooks/:id/deliveries/:deliveryID/redeliver", redeliverWebhookHandler(webhookCtrl))
 	}
 
 	// Группа защищенных маршрутов
 	protected := router.Group("/api/v1")
-	protected.Use(authMiddleware.Handler())
+	protected.Use(authMiddleware.Handler(), authMiddleware.WriteAccess(), writeLimiter.ByUser())
 	{
 		protected.POST("/posts", createPostHandler(postCtrl))
 		protected.PUT("/posts/:id", updatePostHandler(postCtrl))
 		protected.DELETE("/posts/:id", deletePostHandler(postCtrl))
+		protected.POST("/posts/:id/comments", createCommentHandler(commentCtrl))
+		protected.POST("/drafts/:id/publish", publishDraftHandler(draftCtrl, postCtrl, commentCtrl))
+		protected.PUT("/posts/:id/vote", votePostHandler(voteCtrl))
+		protected.DELETE("/posts/:id/vote", unvotePostHandler(voteCtrl))
+		protected.POST("/posts/:id/poll", createPollHandler(pollCtrl))
+		protected.POST("/polls/:id/vote", votePollHandler(pollCtrl))
+		protected.POST("/polls/:id/close", closePollHandler(pollCtrl))
+		protected.PATCH("/me", updateMeHandler(profileCtrl))
+		protected.POST("/me/avatar", uploadAvatarHandler(profileCtrl))
 	}
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	// Health check; /health оставлен для старых проверок и равен /readyz
+	router.GET("/livez", gin.WrapF(probes.Live))
+	router.GET("/readyz", gin.WrapF(probes.Ready))
+	router.GET("/health", gin.WrapF(probes.Ready))
+	router.GET("/metrics", metrics.Handler())
 
 	return router
 }
@@ -69,8 +187,29 @@ func SetupRouter(
 
 func getAllPostsHandler(ctrl controller.PostController) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		posts, err := ctrl.GetAllPosts()
+		var categoryID *uint
+		if raw := c.Query("category_id"); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "invalid category ID",
+					"details": err.Error(),
+				})
+				return
+			}
+			cid := uint(id)
+			categoryID = &cid
+		}
+
+		posts, err := ctrl.GetAllPosts(categoryID, c.Query("sort"), c.GetUint("userID"))
 		if err != nil {
+			if errors.Is(err, controller.ErrValidation) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "failed to get posts",
 				"details": err.Error(),
@@ -81,7 +220,11 @@ func getAllPostsHandler(ctrl controller.PostController) gin.HandlerFunc {
 	}
 }
 
-func getPostByIDHandler(ctrl controller.PostController) gin.HandlerFunc {
+func getPostByIDHandler(
+	ctrl controller.PostController,
+	threadCtrl controller.ThreadController,
+	views service.ViewCounter,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
@@ -92,14 +235,27 @@ func getPostByIDHandler(ctrl controller.PostController) gin.HandlerFunc {
 			return
 		}
 
-		post, err := ctrl.GetPostByID(uint(id))
+		post, err := ctrl.GetPostByID(uint(id), c.GetUint("userID"))
 		if err != nil {
+			// Объединенный пост перенаправляет на обсуждение, в которое он влит
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if target, rerr := threadCtrl.ResolveRedirect(uint(id)); rerr == nil {
+					c.Redirect(http.StatusMovedPermanently, fmt.Sprintf("/api/v1/posts/%d", target))
+					return
+				}
+			}
 			c.JSON(http.StatusNotFound, gin.H{
 				"error":   "post not found",
 				"details": err.Error(),
 			})
 			return
 		}
+
+		// Автор, перечитывающий свой пост, просмотров не добавляет
+		viewerID := c.GetUint("userID")
+		if viewerID != post.AuthorID && views.Record(post.ID, viewerID, c.ClientIP(), c.Request.UserAgent()) {
+			post.ViewCount++
+		}
 		c.JSON(http.StatusOK, post)
 	}
 }
@@ -148,6 +304,13 @@ func createPostHandler(ctrl controller.PostController) gin.HandlerFunc {
 		resp, err := ctrl.CreatePost(&req, authorID)
 		if err != nil {
 			log.Printf("[ERROR] Failed to create post: %v", err)
+			if errors.Is(err, controller.ErrValidation) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "failed to create post",
 				"details": err.Error(),
@@ -178,12 +341,32 @@ func updatePostHandler(ctrl controller.PostController) gin.HandlerFunc {
 			return
 		}
 
-		resp, err := ctrl.UpdatePost(uint(id), &req)
+		actorID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		resp, err := ctrl.UpdatePost(uint(id), &req, actorID, c.GetString("userRole"))
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
 				return
 			}
+			if errors.Is(err, controller.ErrForbidden) {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, controller.ErrLocked) {
+				c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, controller.ErrValidation) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "validation error",
+					"details": err.Error(),
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "update failed",
 				"details": err.Error(),
@@ -205,7 +388,20 @@ func deletePostHandler(ctrl controller.PostController) gin.HandlerFunc {
 			return
 		}
 
-		if err := ctrl.DeletePost(uint(id)); err != nil {
+		actorID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		if err := ctrl.DeletePost(uint(id), actorID, c.GetString("userRole")); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+				return
+			}
+			if errors.Is(err, controller.ErrForbidden) {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "failed to delete post",
 				"details": err.Error(),
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func subscribeHandler(ctrl controller.SubscriptionController, targetType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid " + targetType + " ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var req entity.SubscriptionRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "invalid request body",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		sub, err := ctrl.Subscribe(userID, targetType, uint(targetID), req.Delivery)
+		if err != nil {
+			respondSubscriptionError(c, targetType, err)
+			return
+		}
+		c.JSON(http.StatusOK, sub)
+	}
+}
+
+func unsubscribeHandler(ctrl controller.SubscriptionController, targetType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid " + targetType + " ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if err := ctrl.Unsubscribe(userID, targetType, uint(targetID)); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+				return
+			}
+			respondSubscriptionError(c, targetType, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func getWatchedHandler(ctrl controller.SubscriptionController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		page, limit := parsePagination(c)
+		subs, err := ctrl.GetWatched(userID, c.Query("type"), page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get watched list",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"watched": subs,
+			"page":    page,
+			"limit":   limit,
+		})
+	}
+}
+
+func respondSubscriptionError(c *gin.Context, targetType string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": targetType + " not found"})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "subscription request failed",
+			"details": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTagSuggestions = 10
+	maxTagSuggestions     = 50
+)
+
+func searchTagsHandler(ctrl controller.TagController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTagSuggestions)))
+		if err != nil || limit < 1 {
+			limit = defaultTagSuggestions
+		}
+		if limit > maxTagSuggestions {
+			limit = maxTagSuggestions
+		}
+
+		tags, err := ctrl.SearchTags(c.Query("q"), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to search tags",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, tags)
+	}
+}
+
+func getTagPostsHandler(ctrl controller.TagController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, limit := parsePagination(c)
+		tag, posts, err := ctrl.GetTagPosts(c.Param("tag"), page, limit, c.GetUint("userID"))
+		if err != nil {
+			respondTagError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"tag":   tag,
+			"posts": posts,
+			"page":  page,
+			"limit": limit,
+		})
+	}
+}
+
+func curateTagHandler(ctrl controller.TagController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.TagRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		tag, err := ctrl.CurateTag(req.Name)
+		if err != nil {
+			respondTagError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, tag)
+	}
+}
+
+func renameTagHandler(ctrl controller.TagController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.TagRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		tag, err := ctrl.RenameTag(c.Param("tag"), req.Name)
+		if err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				c.JSON(http.StatusConflict, gin.H{"error": "tag already exists, merge the tags instead"})
+				return
+			}
+			respondTagError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, tag)
+	}
+}
+
+func mergeTagsHandler(ctrl controller.TagController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req entity.TagMergeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		tag, err := ctrl.MergeTags(c.Param("tag"), req.Into)
+		if err != nil {
+			respondTagError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, tag)
+	}
+}
+
+func respondTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "tag request failed",
+			"details": err.Error(),
+		})
+	}
+}
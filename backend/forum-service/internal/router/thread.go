@@ -0,0 +1,125 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func movePostHandler(ctrl controller.ThreadController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, id, ok := moderationParams(c)
+		if !ok {
+			return
+		}
+
+		var req entity.MoveRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		post, err := ctrl.MovePost(id, &req, actorID)
+		if err != nil {
+			respondThreadError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, post)
+	}
+}
+
+func mergePostsHandler(ctrl controller.ThreadController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, id, ok := moderationParams(c)
+		if !ok {
+			return
+		}
+
+		var req entity.MergeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		post, err := ctrl.MergePosts(id, &req, actorID)
+		if err != nil {
+			respondThreadError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, post)
+	}
+}
+
+func splitPostHandler(ctrl controller.ThreadController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actorID, id, ok := moderationParams(c)
+		if !ok {
+			return
+		}
+
+		var req entity.SplitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		post, err := ctrl.SplitPost(id, &req, actorID)
+		if err != nil {
+			respondThreadError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, post)
+	}
+}
+
+func getModerationLogHandler(ctrl controller.ThreadController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, limit := parsePagination(c)
+		entries, err := ctrl.GetModerationLog(page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get moderation log",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"entries": entries,
+			"page":    page,
+			"limit":   limit,
+		})
+	}
+}
+
+func respondThreadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "post or comment not found",
+			"details": err.Error(),
+		})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "moderation action failed",
+			"details": err.Error(),
+		})
+	}
+}
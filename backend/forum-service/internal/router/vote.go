@@ -0,0 +1,105 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func votePostHandler(ctrl controller.VoteController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, postID, ok := voteParams(c)
+		if !ok {
+			return
+		}
+
+		var req entity.PostVoteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		score, err := ctrl.Vote(postID, userID, req.Value)
+		if err != nil {
+			respondVoteError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"score": score, "vote": req.Value})
+	}
+}
+
+func unvotePostHandler(ctrl controller.VoteController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, postID, ok := voteParams(c)
+		if !ok {
+			return
+		}
+
+		score, err := ctrl.Unvote(postID, userID)
+		if err != nil {
+			respondVoteError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"score": score, "vote": 0})
+	}
+}
+
+func getTrendingHandler(ctrl controller.PostController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, limit := parsePagination(c)
+		posts, err := ctrl.GetTrendingPosts(page, limit, c.GetUint("userID"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to get trending posts",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"posts": posts,
+			"page":  page,
+			"limit": limit,
+		})
+	}
+}
+
+func voteParams(c *gin.Context) (uint, uint, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid post ID",
+			"details": err.Error(),
+		})
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func respondVoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to save vote",
+			"details": err.Error(),
+		})
+	}
+}
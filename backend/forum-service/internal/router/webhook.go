@@ -0,0 +1,157 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/controller"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+func getWebhooksHandler(ctrl controller.WebhookController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhooks, err := ctrl.GetWebhooks()
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, webhooks)
+	}
+}
+
+func createWebhookHandler(ctrl controller.WebhookController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		var req entity.WebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		webhook, err := ctrl.CreateWebhook(&req, adminID)
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, webhook)
+	}
+}
+
+func updateWebhookHandler(ctrl controller.WebhookController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := uintParam(c, "id", "invalid webhook ID")
+		if !ok {
+			return
+		}
+
+		var req entity.WebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		webhook, err := ctrl.UpdateWebhook(id, &req)
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, webhook)
+	}
+}
+
+func deleteWebhookHandler(ctrl controller.WebhookController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := uintParam(c, "id", "invalid webhook ID")
+		if !ok {
+			return
+		}
+		if err := ctrl.DeleteWebhook(id); err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func getWebhookDeliveriesHandler(ctrl controller.WebhookController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := uintParam(c, "id", "invalid webhook ID")
+		if !ok {
+			return
+		}
+
+		page, limit := parsePagination(c)
+		deliveries, err := ctrl.GetDeliveries(id, page, limit)
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"deliveries": deliveries,
+			"page":       page,
+			"limit":      limit,
+		})
+	}
+}
+
+func redeliverWebhookHandler(ctrl controller.WebhookController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := uintParam(c, "id", "invalid webhook ID")
+		if !ok {
+			return
+		}
+		deliveryID, ok := uintParam(c, "deliveryID", "invalid delivery ID")
+		if !ok {
+			return
+		}
+
+		delivery, err := ctrl.Redeliver(id, deliveryID)
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, delivery)
+	}
+}
+
+func uintParam(c *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook or delivery not found"})
+	case errors.Is(err, controller.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation error",
+			"details": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "webhook request failed",
+			"details": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+// digestBatchSize - сколько ожидающих записей обрабатывается за один проход
+const digestBatchSize = 1000
+
+// digestLease - на сколько запись закрепляется за репликой. Если письмо
+// не ушло, по истечении срока запись заберет следующий проход.
+const digestLease = 5 * time.Minute
+
+// Mailer отправляет письма пользователям
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type logMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer пишет письма в лог. Используется, пока не настроен SMTP.
+func NewLogMailer(logger *log.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+func (m *logMailer) Send(to, subject, body string) error {
+	m.logger.Printf("Mail to %s: %s\n%s", to, subject, body)
+	return nil
+}
+
+// DigestSender периодически собирает накопленную активность в одно письмо на пользователя
+type DigestSender struct {
+	digests repository.DigestRepository
+	users   repository.UserRepository
+	mailer  Mailer
+	logger  *log.Logger
+}
+
+func NewDigestSender(
+	digests repository.DigestRepository,
+	users repository.UserRepository,
+	mailer Mailer,
+	logger *log.Logger,
+) *DigestSender {
+	return &DigestSender{digests: digests, users: users, mailer: mailer, logger: logger}
+}
+
+// Run отправляет дайджесты каждые interval до отмены ctx
+func (d *DigestSender) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.SendPending(); err != nil {
+				d.logger.Printf("Digest sending failed: %v", err)
+			}
+		}
+	}
+}
+
+// SendPending отправляет все накопленные записи
+func (d *DigestSender) SendPending() error {
+	for {
+		items, err := d.digests.ClaimPending(time.Now(), digestLease, digestBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		byUser := make(map[uint][]string)
+		idsByUser := make(map[uint][]uint)
+		for _, item := range items {
+			byUser[item.UserID] = append(byUser[item.UserID], item.Message)
+			idsByUser[item.UserID] = append(idsByUser[item.UserID], item.ID)
+		}
+
+		for userID, messages := range byUser {
+			user, err := d.users.GetByID(userID)
+			if err != nil {
+				// Записи не помечаются отправленными: после истечения захвата
+				// их заберет следующий проход
+				d.logger.Printf("Postponing digest for user %d: %v", userID, err)
+				continue
+			}
+			body := "- " + strings.Join(messages, "\n- ")
+			subject := fmt.Sprintf("%d new updates in discussions you follow", len(messages))
+			if err := d.mailer.Send(user.Email, subject, body); err != nil {
+				// Записи остаются неотправленными и попадут в дайджест после истечения захвата
+				d.logger.Printf("Failed to send digest to user %d: %v", userID, err)
+				return err
+			}
+			if err := d.digests.MarkSent(idsByUser[userID], time.Now()); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < digestBatchSize {
+			return nil
+		}
+	}
+}
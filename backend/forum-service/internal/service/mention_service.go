@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/mention"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MentionService сохраняет упоминания из постов, уведомляет упомянутых и отображает ссылки на профили
+type MentionService interface {
+	Process(post *entity.Post)
+	Render(posts ...*entity.Post) error
+}
+
+type mentionService struct {
+	users    repository.UserRepository
+	mentions repository.MentionRepository
+	notifier Notifier
+	logger   *log.Logger
+}
+
+func NewMentionService(
+	users repository.UserRepository,
+	mentions repository.MentionRepository,
+	notifier Notifier,
+	logger *log.Logger,
+) MentionService {
+	return &mentionService{
+		users:    users,
+		mentions: mentions,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// Process вызывается после создания или изменения поста. Ошибки только логируются:
+// упоминания не должны мешать публикации.
+func (s *mentionService) Process(post *entity.Post) {
+	for _, username := range mention.Parse(post.Content) {
+		user, err := s.users.GetByUsername(username)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				s.logger.Printf("Failed to resolve mention @%s in post %d: %v", username, post.ID, err)
+			}
+			continue
+		}
+
+		added, err := s.mentions.Add(&entity.Mention{
+			PostID:   post.ID,
+			UserID:   user.ID,
+			Username: user.Username,
+		})
+		if err != nil {
+			s.logger.Printf("Failed to save mention @%s in post %d: %v", username, post.ID, err)
+			continue
+		}
+		if !added {
+			continue
+		}
+
+		if err := s.notifier.Notify(&entity.Notification{
+			UserID:  user.ID,
+			ActorID: &post.AuthorID,
+			Type:    entity.NotificationMention,
+			PostID:  &post.ID,
+			Message: fmt.Sprintf("You were mentioned in %q", post.Title),
+		}); err != nil {
+			s.logger.Printf("Failed to notify @%s about mention in post %d: %v", username, post.ID, err)
+		}
+	}
+}
+
+func (s *mentionService) Render(posts ...*entity.Post) error {
+	ids := make([]uint, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	mentions, err := s.mentions.GetByPosts(ids)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[uint]map[string]bool)
+	for _, m := range mentions {
+		if known[m.PostID] == nil {
+			known[m.PostID] = make(map[string]bool)
+		}
+		known[m.PostID][m.Username] = true
+	}
+
+	for _, p := range posts {
+		p.ContentHTML = mention.Render(p.Content, known[p.ID])
+	}
+	return nil
+}
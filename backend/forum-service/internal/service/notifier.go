@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/events"
+)
+
+// Notifier сохраняет уведомления и доставляет их подключенным клиентам
+type Notifier interface {
+	Notify(notification *entity.Notification) error
+	Subscribe(userID uint) (<-chan *entity.Notification, func())
+}
+
+type notifier struct {
+	repo   repository.NotificationRepository
+	bus    events.Bus
+	logger *log.Logger
+
+	mu          sync.RWMutex
+	subscribers map[uint]map[chan *entity.Notification]struct{}
+}
+
+// notificationEvent - содержимое события notification.created. Получатель передается
+// отдельно, так как в JSON уведомления он скрыт.
+type notificationEvent struct {
+	UserID       uint                 `json:"user_id"`
+	Notification *entity.Notification `json:"notification"`
+}
+
+// NewNotifier подписывает уведомитель на рассылку уведомлений через шину: каждая
+// реплика передает их своим подключенным клиентам
+func NewNotifier(repo repository.NotificationRepository, bus events.Bus, logger *log.Logger) (Notifier, error) {
+	n := &notifier{
+		repo:        repo,
+		bus:         bus,
+		logger:      logger,
+		subscribers: make(map[uint]map[chan *entity.Notification]struct{}),
+	}
+	if err := bus.Broadcast(entity.EventNotificationCreated, n.deliver); err != nil {
+		return nil, fmt.Errorf("subscribe to %s: %w", entity.EventNotificationCreated, err)
+	}
+	return n, nil
+}
+
+func (n *notifier) Notify(notification *entity.Notification) error {
+	// Не уведомляем пользователя о его собственных действиях
+	if notification.ActorID != nil && *notification.ActorID == notification.UserID {
+		return nil
+	}
+
+	enabled, err := n.repo.IsEnabled(notification.UserID, notification.Type)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	if err := n.repo.Create(notification); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(notificationEvent{UserID: notification.UserID, Notification: notification})
+	if err != nil {
+		return err
+	}
+	if err := n.bus.Publish(context.Background(), &events.Event{
+		ID:          fmt.Sprintf("notification:%d", notification.ID),
+		Type:        entity.EventNotificationCreated,
+		AggregateID: fmt.Sprintf("user:%d", notification.UserID),
+		OccurredAt:  notification.CreatedAt,
+		Payload:     payload,
+	}); err != nil {
+		// Уведомление уже сохранено; без шины его получат хотя бы клиенты этой реплики
+		n.logger.Printf("Failed to broadcast notification %d: %v", notification.ID, err)
+		n.publish(notification)
+	}
+	return nil
+}
+
+func (n *notifier) deliver(ctx context.Context, event *events.Event) error {
+	var payload notificationEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("decode %s: %w", event.Type, err)
+	}
+	if payload.Notification == nil {
+		return nil
+	}
+	payload.Notification.UserID = payload.UserID
+	n.publish(payload.Notification)
+	return nil
+}
+
+// Subscribe возвращает канал живых уведомлений пользователя и функцию отписки
+func (n *notifier) Subscribe(userID uint) (<-chan *entity.Notification, func()) {
+	ch := make(chan *entity.Notification, 16)
+
+	n.mu.Lock()
+	if n.subscribers[userID] == nil {
+		n.subscribers[userID] = make(map[chan *entity.Notification]struct{})
+	}
+	n.subscribers[userID][ch] = struct{}{}
+	n.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			n.mu.Lock()
+			delete(n.subscribers[userID], ch)
+			if len(n.subscribers[userID]) == 0 {
+				delete(n.subscribers, userID)
+			}
+			n.mu.Unlock()
+		})
+	}
+}
+
+func (n *notifier) publish(notification *entity.Notification) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for ch := range n.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+			// Медленный клиент получит уведомление при следующем запросе списка
+			n.logger.Printf("Dropping live notification %d for user %d: subscriber is full", notification.ID, notification.UserID)
+		}
+	}
+}
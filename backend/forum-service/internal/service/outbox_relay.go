@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/events"
+)
+
+// outboxBatchSize - сколько событий отправляется за один проход
+const outboxBatchSize = 100
+
+// OutboxRelay переносит события из таблицы outbox в шину. Доставка "хотя бы раз":
+// если отметка об отправке не сохранилась, событие уйдет повторно с тем же ID.
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository
+	publisher events.Publisher
+	logger    *log.Logger
+}
+
+func NewOutboxRelay(outbox repository.OutboxRepository, publisher events.Publisher, logger *log.Logger) *OutboxRelay {
+	return &OutboxRelay{outbox: outbox, publisher: publisher, logger: logger}
+}
+
+// Run отправляет накопленные события каждые interval до отмены ctx
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayPending(ctx); err != nil {
+				r.logger.Printf("Outbox relay failed: %v", err)
+			}
+		}
+	}
+}
+
+// CheckBacklog - проверка готовности: больше max неотправленных событий
+// означает, что шина недоступна или relay не успевает
+func (r *OutboxRelay) CheckBacklog(max int64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		n, err := r.outbox.CountPending(ctx)
+		if err != nil {
+			return err
+		}
+		if n > max {
+			return fmt.Errorf("%d events pending in outbox, limit %d", n, max)
+		}
+		return nil
+	}
+}
+
+// RelayPending отправляет все неотправленные события и возвращает их число
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.outbox.PublishPending(outboxBatchSize, func(e *entity.OutboxEvent) error {
+			return r.publisher.Publish(ctx, &events.Event{
+				ID:          fmt.Sprintf("forum-service:%d", e.ID),
+				Type:        e.EventType,
+				AggregateID: e.AggregateType + ":" + e.AggregateID,
+				OccurredAt:  e.CreatedAt,
+				Version:     e.ID,
+				Payload:     e.Payload,
+			})
+		})
+		total += n
+		if err != nil || n < outboxBatchSize {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+// DefaultRankingParams - веса по умолчанию: комментарий ценнее голоса, просмотр почти ничего не стоит
+var DefaultRankingParams = repository.RankingParams{
+	VoteWeight:     1,
+	CommentWeight:  2,
+	ViewWeight:     0.01,
+	Gravity:        1.8,
+	TrendingWindow: 7 * 24 * time.Hour,
+}
+
+// RankingService периодически пересчитывает оценки постов для сортировки "hot" и трендов.
+// Пересчет идемпотентен, поэтому может идти в каждой реплике.
+type RankingService struct {
+	ranks  repository.RankingRepository
+	params repository.RankingParams
+	logger *log.Logger
+}
+
+func NewRankingService(ranks repository.RankingRepository, params repository.RankingParams, logger *log.Logger) *RankingService {
+	return &RankingService{ranks: ranks, params: params, logger: logger}
+}
+
+// Run пересчитывает оценки сразу и затем каждые interval до отмены ctx
+func (s *RankingService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ranks.Refresh(s.params, time.Now()); err != nil {
+			s.logger.Printf("Ranking refresh failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ScheduledPublisher публикует отложенные посты, время которых наступило
+type ScheduledPublisher interface {
+	PublishDue() (int, error)
+}
+
+// PublishScheduler периодически публикует отложенные посты. Его можно запускать
+// в каждой реплике: пост забирается атомарно, поэтому побочные эффекты публикации
+// выполняются один раз.
+type PublishScheduler struct {
+	publisher ScheduledPublisher
+	logger    *log.Logger
+}
+
+func NewPublishScheduler(publisher ScheduledPublisher, logger *log.Logger) *PublishScheduler {
+	return &PublishScheduler{publisher: publisher, logger: logger}
+}
+
+// Run проверяет отложенные посты каждые interval до отмены ctx
+func (s *PublishScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := s.publisher.PublishDue()
+			if err != nil {
+				s.logger.Printf("Scheduled publishing failed: %v", err)
+			}
+			if published > 0 {
+				s.logger.Printf("Published %d scheduled posts", published)
+			}
+		}
+	}
+}
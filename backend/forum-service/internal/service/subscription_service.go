@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/events"
+)
+
+// ActivityDelivery доставляет активность одному подписчику выбранным им способом
+type ActivityDelivery interface {
+	Deliver(userID uint, activity *entity.Activity) error
+}
+
+// SubscriptionService рассылает новую активность подписчикам поста и его категории
+type SubscriptionService interface {
+	// Publish ставит активность в outbox; рассылка идет в фоне после доставки события
+	Publish(activity *entity.Activity)
+	// Subscribe подписывает рассылку на события активности из шины
+	Subscribe(sub events.Subscriber) error
+}
+
+type subscriptionService struct {
+	subs       repository.SubscriptionRepository
+	outbox     repository.OutboxRepository
+	deliveries map[string]ActivityDelivery
+	logger     *log.Logger
+}
+
+// NewSubscriptionService принимает доставщиков по имени способа доставки (entity.Delivery*)
+func NewSubscriptionService(
+	subs repository.SubscriptionRepository,
+	outbox repository.OutboxRepository,
+	deliveries map[string]ActivityDelivery,
+	logger *log.Logger,
+) SubscriptionService {
+	return &subscriptionService{subs: subs, outbox: outbox, deliveries: deliveries, logger: logger}
+}
+
+func (s *subscriptionService) Publish(activity *entity.Activity) {
+	event, err := entity.NewActivityEvent(activity)
+	if err == nil {
+		err = s.outbox.Add(event)
+	}
+	if err != nil {
+		s.logger.Printf("Failed to queue %s activity for post %d: %v", activity.Kind, activity.PostID, err)
+	}
+}
+
+func (s *subscriptionService) Subscribe(sub events.Subscriber) error {
+	if err := sub.Subscribe(entity.EventActivityPublished, s.handle); err != nil {
+		return fmt.Errorf("subscribe to %s: %w", entity.EventActivityPublished, err)
+	}
+	return nil
+}
+
+func (s *subscriptionService) handle(ctx context.Context, event *events.Event) error {
+	var activity entity.Activity
+	if err := json.Unmarshal(event.Payload, &activity); err != nil {
+		return fmt.Errorf("decode %s: %w", event.Type, err)
+	}
+	s.fanOut(&activity)
+	return nil
+}
+
+// fanOut доставляет активность каждому подписчику выбранным им способом
+func (s *subscriptionService) fanOut(activity *entity.Activity) {
+	type target struct {
+		kind string
+		id   uint
+	}
+	targets := []target{{entity.SubscriptionPost, activity.PostID}}
+	if activity.CategoryID != nil {
+		targets = append(targets, target{entity.SubscriptionCategory, *activity.CategoryID})
+	}
+
+	// Подписчик поста и категории получает событие один раз, автор события - ни разу
+	delivered := map[uint]bool{activity.ActorID: true}
+	for _, id := range activity.AlreadyNotified {
+		delivered[id] = true
+	}
+	for _, target := range targets {
+		subs, err := s.subs.GetSubscribers(target.kind, target.id)
+		if err != nil {
+			s.logger.Printf("Failed to load %s %d subscribers: %v", target.kind, target.id, err)
+			continue
+		}
+
+		for _, sub := range subs {
+			if delivered[sub.UserID] {
+				continue
+			}
+			delivered[sub.UserID] = true
+
+			delivery, ok := s.deliveries[sub.Delivery]
+			if !ok {
+				s.logger.Printf("Unknown delivery %q for subscription %d", sub.Delivery, sub.ID)
+				continue
+			}
+			if err := delivery.Deliver(sub.UserID, activity); err != nil {
+				s.logger.Printf("Failed to deliver %s to user %d: %v", activity.Kind, sub.UserID, err)
+			}
+		}
+	}
+}
+
+type inAppDelivery struct {
+	notifier Notifier
+}
+
+// NewInAppDelivery доставляет активность в виде уведомления на сайте
+func NewInAppDelivery(notifier Notifier) ActivityDelivery {
+	return &inAppDelivery{notifier: notifier}
+}
+
+func (d *inAppDelivery) Deliver(userID uint, activity *entity.Activity) error {
+	postID := activity.PostID
+	actorID := activity.ActorID
+	return d.notifier.Notify(&entity.Notification{
+		UserID:    userID,
+		ActorID:   &actorID,
+		Type:      entity.NotificationActivity,
+		PostID:    &postID,
+		CommentID: activity.CommentID,
+		Message:   activity.Message,
+	})
+}
+
+type emailDigestDelivery struct {
+	digests repository.DigestRepository
+}
+
+// NewEmailDigestDelivery откладывает активность до очередной отправки email-дайджеста
+func NewEmailDigestDelivery(digests repository.DigestRepository) ActivityDelivery {
+	return &emailDigestDelivery{digests: digests}
+}
+
+func (d *emailDigestDelivery) Deliver(userID uint, activity *entity.Activity) error {
+	if err := d.digests.Add(&entity.DigestItem{
+		UserID:  userID,
+		PostID:  activity.PostID,
+		Message: activity.Message,
+	}); err != nil {
+		return fmt.Errorf("queue digest item: %w", err)
+	}
+	return nil
+}
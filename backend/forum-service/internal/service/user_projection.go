@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"github.com/lera-guryan2222/forum/backend/forum-service/pkg/events"
+)
+
+// UserProjection держит локальную копию пользователей и их санкций в соответствии
+// с auth-service, применяя его события
+type UserProjection struct {
+	projections repository.ProjectionRepository
+	logger      *log.Logger
+}
+
+func NewUserProjection(projections repository.ProjectionRepository, logger *log.Logger) *UserProjection {
+	return &UserProjection{projections: projections, logger: logger}
+}
+
+// Subscribe подписывает проекцию на события пользователей
+func (p *UserProjection) Subscribe(sub events.Subscriber) error {
+	handlers := map[string]events.Handler{
+		entity.EventUserRegistered: p.applyUser,
+		entity.EventUserUpdated:    p.applyUser,
+		entity.EventUserBanned:     p.applySanction,
+		entity.EventUserMuted:      p.applySanction,
+		entity.EventSanctionLifted: p.applySanction,
+	}
+	for eventType, handler := range handlers {
+		if err := sub.Subscribe(eventType, handler); err != nil {
+			return fmt.Errorf("subscribe to %s: %w", eventType, err)
+		}
+	}
+	return nil
+}
+
+func (p *UserProjection) applyUser(ctx context.Context, event *events.Event) error {
+	var payload entity.UserEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("decode %s: %w", event.Type, err)
+	}
+
+	return p.projections.ApplyUser(ctx, event.ID, &entity.User{
+		ID:       payload.ID,
+		Username: payload.Username,
+		Email:    payload.Email,
+		Role:     payload.Role,
+		Version:  event.Version,
+	})
+}
+
+func (p *UserProjection) applySanction(ctx context.Context, event *events.Event) error {
+	var payload entity.SanctionEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("decode %s: %w", event.Type, err)
+	}
+
+	if err := p.projections.ApplySanction(ctx, event.ID, &entity.Sanction{
+		ID:        payload.ID,
+		UserID:    payload.UserID,
+		Type:      payload.Type,
+		Reason:    payload.Reason,
+		StartsAt:  payload.StartsAt,
+		ExpiresAt: payload.ExpiresAt,
+		LiftedAt:  payload.LiftedAt,
+		Version:   event.Version,
+	}); err != nil {
+		return err
+	}
+	p.logger.Printf("Applied %s for user %d", event.Type, payload.UserID)
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	userSyncTimeout  = 10 * time.Second
+	userSyncPageSize = 100
+)
+
+// ErrUnknownUser - в auth-service нет пользователя с таким ID
+var ErrUnknownUser = errors.New("user is unknown to auth-service")
+
+// userSnapshot - снимок пользователя из /internal/users auth-service
+type userSnapshot struct {
+	User      entity.UserEventPayload       `json:"user"`
+	Sanctions []entity.SanctionEventPayload `json:"sanctions"`
+	Version   int64                         `json:"version"`
+}
+
+// UserSync сверяет проекцию пользователей со снимками auth-service. Core NATS не
+// хранит события, и все, что пришло, пока форум был недоступен, восстанавливается отсюда:
+// при старте и периодически целиком, а при запросе неизвестного пользователя - точечно.
+type UserSync struct {
+	projections repository.ProjectionRepository
+	client      *http.Client
+	baseURL     string
+	token       string
+	logger      *log.Logger
+}
+
+func NewUserSync(projections repository.ProjectionRepository, baseURL, token string, logger *log.Logger) *UserSync {
+	return &UserSync{
+		projections: projections,
+		client:      &http.Client{Timeout: userSyncTimeout, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		token:       token,
+		logger:      logger,
+	}
+}
+
+// Run сверяет всех пользователей сразу и затем каждые interval до отмены ctx
+func (s *UserSync) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.SyncAll(ctx); err != nil {
+			s.logger.Printf("User resync failed after %d users: %v", n, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncAll применяет снимки всех пользователей и возвращает их число
+func (s *UserSync) SyncAll(ctx context.Context) (int, error) {
+	total := 0
+	var after uint
+	for {
+		var page []*userSnapshot
+		query := url.Values{"after": {fmt.Sprint(after)}, "limit": {fmt.Sprint(userSyncPageSize)}}
+		if err := s.get(ctx, "/internal/users?"+query.Encode(), &page); err != nil {
+			return total, err
+		}
+		for _, snapshot := range page {
+			if err := s.apply(ctx, snapshot); err != nil {
+				return total, err
+			}
+			after = snapshot.User.ID
+			total++
+		}
+		if len(page) < userSyncPageSize {
+			return total, nil
+		}
+	}
+}
+
+// SyncUser применяет снимок одного пользователя
+func (s *UserSync) SyncUser(ctx context.Context, userID uint) error {
+	var snapshot userSnapshot
+	if err := s.get(ctx, fmt.Sprintf("/internal/users/%d", userID), &snapshot); err != nil {
+		return err
+	}
+	return s.apply(ctx, &snapshot)
+}
+
+// apply применяет снимок как событие с его версией: более новое событие, уже
+// примененное проекцией, снимок не перезапишет
+func (s *UserSync) apply(ctx context.Context, snapshot *userSnapshot) error {
+	user := snapshot.User
+	err := s.projections.ApplyUser(ctx, fmt.Sprintf("auth-service:snapshot:user:%d:%d", user.ID, snapshot.Version), &entity.User{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+		Version:  snapshot.Version,
+	})
+	if err != nil {
+		return fmt.Errorf("apply user %d: %w", user.ID, err)
+	}
+
+	for _, sanction := range snapshot.Sanctions {
+		eventID := fmt.Sprintf("auth-service:snapshot:sanction:%d:%d", sanction.ID, snapshot.Version)
+		if err := s.projections.ApplySanction(ctx, eventID, &entity.Sanction{
+			ID:        sanction.ID,
+			UserID:    sanction.UserID,
+			Type:      sanction.Type,
+			Reason:    sanction.Reason,
+			StartsAt:  sanction.StartsAt,
+			ExpiresAt: sanction.ExpiresAt,
+			LiftedAt:  sanction.LiftedAt,
+			Version:   snapshot.Version,
+		}); err != nil {
+			return fmt.Errorf("apply sanction %d: %w", sanction.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *UserSync) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrUnknownUser
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("auth-service responded %s to %s", resp.Status, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
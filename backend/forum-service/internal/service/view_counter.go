@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+// Подстроки User-Agent, по которым запрос считается запросом робота
+var botMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "preview", "fetch", "monitor",
+	"curl", "wget", "python-requests", "go-http-client", "headless", "phantomjs",
+}
+
+// ViewCounter считает просмотры постов
+type ViewCounter interface {
+	// Record учитывает просмотр, если читатель не смотрел пост в пределах окна
+	// и не похож на робота. Возвращает, был ли просмотр засчитан.
+	Record(postID, viewerID uint, ip, userAgent string) bool
+}
+
+// BatchedViewCounter копит просмотры в памяти и периодически записывает их
+// одним запросом, чтобы чтение поста не превращалось в запись в базу
+type BatchedViewCounter struct {
+	posts  repository.PostRepository
+	window time.Duration
+	logger *log.Logger
+
+	mu      sync.Mutex
+	seen    map[string]time.Time
+	pending map[uint]int64
+}
+
+func NewBatchedViewCounter(posts repository.PostRepository, window time.Duration, logger *log.Logger) *BatchedViewCounter {
+	return &BatchedViewCounter{
+		posts:   posts,
+		window:  window,
+		logger:  logger,
+		seen:    make(map[string]time.Time),
+		pending: make(map[uint]int64),
+	}
+}
+
+func (v *BatchedViewCounter) Record(postID, viewerID uint, ip, userAgent string) bool {
+	if isBot(userAgent) {
+		return false
+	}
+
+	// Авторизованный читатель узнается по ID с любого адреса, анонимный - по IP
+	key := fmt.Sprintf("%d:ip:%s", postID, ip)
+	if viewerID != 0 {
+		key = fmt.Sprintf("%d:user:%d", postID, viewerID)
+	}
+
+	now := time.Now()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if last, ok := v.seen[key]; ok && now.Sub(last) < v.window {
+		return false
+	}
+	v.seen[key] = now
+	v.pending[postID]++
+	return true
+}
+
+// Run записывает накопленные просмотры каждые interval. После отмены ctx
+// выполняется последняя запись, чтобы не потерять просмотры при остановке.
+func (v *BatchedViewCounter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := v.Flush(); err != nil {
+				v.logger.Printf("Final view flush failed: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := v.Flush(); err != nil {
+				v.logger.Printf("View flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// Flush записывает накопленные просмотры и забывает истекшие окна
+func (v *BatchedViewCounter) Flush() error {
+	v.mu.Lock()
+	batch := v.pending
+	v.pending = make(map[uint]int64)
+	now := time.Now()
+	for key, at := range v.seen {
+		if now.Sub(at) >= v.window {
+			delete(v.seen, key)
+		}
+	}
+	v.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := v.posts.AddViews(batch); err != nil {
+		// Возвращаем просмотры в очередь, они уйдут со следующей записью
+		v.mu.Lock()
+		for postID, n := range batch {
+			v.pending[postID] += n
+		}
+		v.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func isBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
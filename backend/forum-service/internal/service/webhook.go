@@ -0,0 +1,228 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	// webhookMaxAttempts - после стольких неудачных попыток доставка считается проваленной
+	webhookMaxAttempts = 8
+	// Задержка перед повтором удваивается с каждой попыткой: 15s, 30s, 1m, ... но не больше часа
+	webhookBaseBackoff = 15 * time.Second
+	webhookMaxBackoff  = time.Hour
+	// webhookLease - на сколько доставка закрепляется за репликой, которая ее отправляет
+	webhookLease     = 2 * time.Minute
+	webhookBatchSize = 50
+	webhookTimeout   = 10 * time.Second
+)
+
+// WebhookDispatcher ставит события форума в очередь на отправку подписанным вебхукам
+type WebhookDispatcher interface {
+	Dispatch(event string, data interface{})
+}
+
+// WebhookEnvelope - тело запроса, отправляемого на вебхук
+type WebhookEnvelope struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookSender сохраняет доставки и отправляет их в фоне с повторами.
+// Тело подписывается HMAC-SHA256 секретом вебхука: заголовок X-Forum-Signature: sha256=<hex>.
+type WebhookSender struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	logger *log.Logger
+	wake   chan struct{}
+}
+
+func NewWebhookSender(repo repository.WebhookRepository, logger *log.Logger) *WebhookSender {
+	return &WebhookSender{
+		repo: repo,
+		// Каждая отправка - клиентский спан, получатель видит traceparent
+		client: &http.Client{Timeout: webhookTimeout, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Dispatch не отправляет запросы сам, поэтому не задерживает обработку запроса пользователя
+func (s *WebhookSender) Dispatch(event string, data interface{}) {
+	webhooks, err := s.repo.ListActive()
+	if err != nil {
+		s.logger.Printf("Failed to load webhooks for %s: %v", event, err)
+		return
+	}
+
+	var payload []byte
+	now := time.Now()
+	var deliveries []*entity.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(WebhookEnvelope{Event: event, OccurredAt: now, Data: data}); err != nil {
+				s.logger.Printf("Failed to encode %s payload: %v", event, err)
+				return
+			}
+		}
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        entity.DeliveryPending,
+			NextAttemptAt: &now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	if err := s.repo.CreateDeliveries(deliveries); err != nil {
+		s.logger.Printf("Failed to queue %s deliveries: %v", event, err)
+		return
+	}
+	s.Wake()
+}
+
+// Wake просит фоновую отправку не ждать следующего тика
+func (s *WebhookSender) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run отправляет ожидающие доставки каждые interval и по сигналу Wake до отмены ctx
+func (s *WebhookSender) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		if err := s.SendDue(ctx); err != nil {
+			s.logger.Printf("Webhook sending failed: %v", err)
+		}
+	}
+}
+
+// SendDue отправляет все доставки, время которых наступило
+func (s *WebhookSender) SendDue(ctx context.Context) error {
+	for {
+		deliveries, err := s.repo.ClaimDue(time.Now(), webhookLease, webhookBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			s.attempt(ctx, delivery)
+			if err := s.repo.SaveDelivery(delivery); err != nil {
+				s.logger.Printf("Failed to save webhook delivery %d: %v", delivery.ID, err)
+			}
+		}
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *WebhookSender) attempt(ctx context.Context, delivery *entity.WebhookDelivery) {
+	delivery.Attempts++
+	now := time.Now()
+
+	if delivery.Webhook == nil || !delivery.Webhook.Active {
+		delivery.Status = entity.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "webhook is disabled"
+		return
+	}
+
+	status, err := s.send(ctx, delivery)
+	delivery.LastStatusCode = status
+	if err == nil {
+		delivery.Status = entity.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = truncate(err.Error(), 1000)
+	if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = entity.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(webhookBackoff(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+func (s *WebhookSender) send(ctx context.Context, delivery *entity.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "forum-webhooks/1.0")
+	req.Header.Set("X-Forum-Event", delivery.Event)
+	req.Header.Set("X-Forum-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Forum-Signature", "sha256="+Sign(delivery.Webhook.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает HMAC-SHA256 тела в hex. Получатель сравнивает его с заголовком X-Forum-Signature.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookBackoff(attempt int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempt && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxBackoff {
+		delay = webhookMaxBackoff
+	}
+	return delay
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/entity"
+	"github.com/lera-guryan2222/forum/backend/forum-service/internal/repository"
+)
+
+// fakeWebhookRepo хранит доставки в памяти; остальные методы репозитория тестам не нужны
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+	deliveries []*entity.WebhookDelivery
+	saved      []entity.WebhookDelivery
+}
+
+func (r *fakeWebhookRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	var due []*entity.WebhookDelivery
+	for _, d := range r.deliveries {
+		if len(due) == limit {
+			break
+		}
+		if d.Status == entity.DeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			leased := now.Add(lease)
+			d.NextAttemptAt = &leased
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeWebhookRepo) SaveDelivery(delivery *entity.WebhookDelivery) error {
+	r.saved = append(r.saved, *delivery)
+	return nil
+}
+
+func newTestSender(repo repository.WebhookRepository) *WebhookSender {
+	return NewWebhookSender(repo, log.New(io.Discard, "", 0))
+}
+
+func newTestDelivery(url string) *entity.WebhookDelivery {
+	now := time.Now()
+	return &entity.WebhookDelivery{
+		ID:            7,
+		WebhookID:     3,
+		Webhook:       &entity.Webhook{ID: 3, URL: url, Secret: "s3cret", Active: true},
+		Event:         entity.EventPostCreated,
+		Payload:       `{"event":"post.created"}`,
+		Status:        entity.DeliveryPending,
+		NextAttemptAt: &now,
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Общеизвестное значение HMAC-SHA256 для ключа "key" и этой фразы
+	got := Sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+	want := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 15 * time.Second},
+		{2, 30 * time.Second},
+		{3, time.Minute},
+		{8, 32 * time.Minute},
+		{9, time.Hour},
+		{30, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempt); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestAttemptSignsAndDelivers(t *testing.T) {
+	var header http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		header, body = r.Header.Clone(), string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	delivery := newTestDelivery(server.URL)
+	newTestSender(&fakeWebhookRepo{}).attempt(context.Background(), delivery)
+
+	if delivery.Status != entity.DeliverySucceeded || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Fatalf("delivery not marked delivered: %+v", delivery)
+	}
+	if delivery.Attempts != 1 || delivery.LastStatusCode != http.StatusNoContent {
+		t.Fatalf("attempts = %d, status = %d", delivery.Attempts, delivery.LastStatusCode)
+	}
+	if body != delivery.Payload {
+		t.Fatalf("body = %q, want %q", body, delivery.Payload)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(body))
+	if got, want := header.Get("X-Forum-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("X-Forum-Signature = %q, want %q", got, want)
+	}
+	if got := header.Get("X-Forum-Event"); got != entity.EventPostCreated {
+		t.Errorf("X-Forum-Event = %q", got)
+	}
+	if got := header.Get("X-Forum-Delivery"); got != "7" {
+		t.Errorf("X-Forum-Delivery = %q", got)
+	}
+}
+
+func TestAttemptSchedulesRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	delivery := newTestDelivery(server.URL)
+	before := time.Now()
+	newTestSender(&fakeWebhookRepo{}).attempt(context.Background(), delivery)
+
+	if delivery.Status != entity.DeliveryPending || delivery.Attempts != 1 {
+		t.Fatalf("status = %s, attempts = %d", delivery.Status, delivery.Attempts)
+	}
+	if delivery.LastStatusCode != http.StatusBadGateway || !strings.Contains(delivery.LastError, "502") {
+		t.Fatalf("failure not recorded: code %d, error %q", delivery.LastStatusCode, delivery.LastError)
+	}
+	if delivery.NextAttemptAt == nil || delivery.NextAttemptAt.Before(before.Add(webhookBaseBackoff)) {
+		t.Fatalf("next attempt %v is earlier than the backoff", delivery.NextAttemptAt)
+	}
+}
+
+func TestAttemptGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	delivery := newTestDelivery(server.URL)
+	delivery.Attempts = webhookMaxAttempts - 1
+	newTestSender(&fakeWebhookRepo{}).attempt(context.Background(), delivery)
+
+	if delivery.Status != entity.DeliveryFailed || delivery.NextAttemptAt != nil {
+		t.Fatalf("delivery not failed after %d attempts: %+v", delivery.Attempts, delivery)
+	}
+}
+
+func TestAttemptSkipsDisabledWebhook(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	delivery := newTestDelivery(server.URL)
+	delivery.Webhook.Active = false
+	newTestSender(&fakeWebhookRepo{}).attempt(context.Background(), delivery)
+
+	if hits.Load() != 0 {
+		t.Fatal("disabled webhook was called")
+	}
+	if delivery.Status != entity.DeliveryFailed || delivery.LastError == "" {
+		t.Fatalf("delivery not failed: %+v", delivery)
+	}
+}
+
+func TestSendDueSavesEveryAttempt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Forum-Delivery") == "1" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ok, failing := newTestDelivery(server.URL), newTestDelivery(server.URL)
+	ok.ID, failing.ID = 1, 2
+	repo := &fakeWebhookRepo{deliveries: []*entity.WebhookDelivery{ok, failing}}
+	if err := newTestSender(repo).SendDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.saved) != 2 {
+		t.Fatalf("saved %d deliveries, want 2", len(repo.saved))
+	}
+	for _, d := range repo.saved {
+		switch d.ID {
+		case 1:
+			if d.Status != entity.DeliverySucceeded {
+				t.Errorf("delivery 1: status %s", d.Status)
+			}
+		case 2:
+			if d.Status != entity.DeliveryPending || d.LastStatusCode != http.StatusServiceUnavailable {
+				t.Errorf("delivery 2: status %s, code %d", d.Status, d.LastStatusCode)
+			}
+		}
+	}
+
+	// Отложенная на повтор доставка не уходит до истечения задержки
+	repo.saved = nil
+	if err := newTestSender(repo).SendDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.saved) != 0 {
+		t.Fatalf("retried %d deliveries before the backoff", len(repo.saved))
+	}
+}
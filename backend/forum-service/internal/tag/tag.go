@@ -0,0 +1,69 @@
+// Package tag проверяет и приводит к единому виду названия тегов.
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPerPost ограничивает число тегов у одного поста
+	MaxPerPost = 5
+	MinLength  = 2
+	MaxLength  = 32
+)
+
+var ErrInvalid = errors.New("invalid tag")
+
+// Normalize приводит название к нижнему регистру, убирает ведущий "#" и заменяет
+// пробелы и подчеркивания дефисами: "#Go Lang" и "go_lang" дают "go-lang"
+func Normalize(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#")))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case r == '-' || r == '_' || unicode.IsSpace(r):
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		default:
+			return "", fmt.Errorf("%w: %q contains %q", ErrInvalid, name, r)
+		}
+	}
+
+	normalized := strings.TrimRight(b.String(), "-")
+	if n := utf8.RuneCountInString(normalized); n < MinLength || n > MaxLength {
+		return "", fmt.Errorf("%w: %q must be %d to %d characters long", ErrInvalid, name, MinLength, MaxLength)
+	}
+	return normalized, nil
+}
+
+// NormalizeAll нормализует список тегов поста, убирая повторы
+func NormalizeAll(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized, err := Normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+	if len(result) > MaxPerPost {
+		return nil, fmt.Errorf("%w: at most %d tags per post", ErrInvalid, MaxPerPost)
+	}
+	return result, nil
+}
@@ -1,40 +1,41 @@
 package auth
 
 import (
-	"time"
+	"errors"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var jwtKey = []byte("your_secret_key")
-
+// Claims - access-токен, выданный auth-service
 type Claims struct {
-	Username string `json:"username"`
+	UserID uint `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(username string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		Username: username,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtKey)
+// Validator проверяет access-токены auth-service ключом, которым он их подписывает.
+// Сам форум токены не выдает.
+type Validator struct {
+	secret []byte
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
+func NewValidator(secret string) *Validator {
+	return &Validator{secret: []byte(secret)}
+}
+
+func (v *Validator) Validate(tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
+	token, err := jwt.ParseWithClaims(strings.TrimPrefix(tokenString, "Bearer "), claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return v.secret, nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	if !token.Valid {
-		return nil, err
+	if !token.Valid || claims.UserID == 0 {
+		return nil, errors.New("invalid token")
 	}
 	return claims, nil
 }
@@ -0,0 +1,51 @@
+// Package events описывает обмен доменными событиями между сервисами
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event - сообщение о доменном изменении. ID уникален в пределах всех сервисов,
+// поэтому потребитель может по нему отбрасывать повторные доставки.
+type Event struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+	// Version растет с каждым событием агрегата: потребитель, получивший события
+	// не по порядку, не применяет более старое поверх более нового
+	Version int64 `json:"version,omitempty"`
+	// Trace - заголовки W3C traceparent/tracestate: обработка события
+	// продолжает трассу, в которой оно было отправлено
+	Trace map[string]string `json:"trace,omitempty"`
+}
+
+// Handler обрабатывает одно событие
+type Handler func(ctx context.Context, event *Event) error
+
+// Publisher отправляет события. Успешный возврат означает, что событие принято шиной.
+type Publisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// Subscriber доставляет события указанного типа обработчику
+type Subscriber interface {
+	Subscribe(eventType string, handler Handler) error
+}
+
+// Broadcaster доставляет событие каждому экземпляру сервиса, а не одному из них.
+// Подходит для состояния, которое живет в памяти реплики, например открытых SSE-потоков.
+type Broadcaster interface {
+	Broadcast(eventType string, handler Handler) error
+}
+
+// Bus - шина, через которую сервис и публикует, и получает события
+type Bus interface {
+	Publisher
+	Subscriber
+	Broadcaster
+	Close() error
+}
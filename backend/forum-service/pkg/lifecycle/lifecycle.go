@@ -0,0 +1,212 @@
+// Package lifecycle запускает HTTP-серверы и фоновые задачи сервиса и
+// останавливает их по SIGTERM/SIGINT в порядке, при котором не теряются
+// запросы и данные
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+type closer struct {
+	name string
+	fn   func() error
+}
+
+// App останавливается так:
+//  1. Ready начинает возвращать false, и балансировщик за DrainDelay
+//     перестает направлять новые запросы;
+//  2. серверы перестают принимать соединения, закрывается канал Stopping,
+//     по которому завершаются долгие потоки, и App ждет текущих запросов;
+//  3. отменяется контекст фоновых задач, App ждет их завершения;
+//  4. ресурсы из OnClose закрываются в обратном порядке, поэтому база,
+//     зарегистрированная первой, закрывается последней.
+//
+// Шаги 2 и 3 ждут не дольше ShutdownTimeout каждый. Если запросы или задачи
+// за это время не завершились, шаг 4 пропускается: закрыть базу под работающей
+// задачей хуже, чем выйти с открытыми соединениями.
+// Повторный сигнал во время остановки завершает процесс сразу.
+type App struct {
+	DrainDelay      time.Duration
+	ShutdownTimeout time.Duration
+
+	logger   *log.Logger
+	ready    atomic.Bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping chan struct{}
+	stopOnce sync.Once
+	workers  sync.WaitGroup
+	servers  []*http.Server
+	closers  []closer
+	errs     chan error
+}
+
+type stoppingKey struct{}
+
+// Stopping возвращает канал, который закрывается в начале остановки сервера,
+// обслужившего запрос с контекстом ctx. Shutdown не отменяет контекст запроса,
+// поэтому потоки вроде SSE должны завершаться по этому каналу, иначе остановка
+// ждет их до ShutdownTimeout. Вне App канал nil и никогда не срабатывает.
+func Stopping(ctx context.Context) <-chan struct{} {
+	ch, _ := ctx.Value(stoppingKey{}).(chan struct{})
+	return ch
+}
+
+func New(logger *log.Logger, drainDelay, shutdownTimeout time.Duration) *App {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &App{
+		DrainDelay:      drainDelay,
+		ShutdownTimeout: shutdownTimeout,
+		logger:          logger,
+		ctx:             ctx,
+		cancel:          cancel,
+		stopping:        make(chan struct{}),
+		errs:            make(chan error, 1),
+	}
+}
+
+// Ready сообщает, принимает ли сервис трафик: true после Run и до начала остановки
+func (a *App) Ready() bool {
+	return a.ready.Load()
+}
+
+// Go запускает фоновую задачу. ctx отменяется, когда HTTP уже остановлен,
+// так что запросы, завершающиеся во время остановки, еще могут ставить задачи.
+func (a *App) Go(name string, fn func(ctx context.Context)) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		fn(a.ctx)
+		a.logger.Printf("%s stopped", name)
+	}()
+}
+
+// Serve запускает сервер; ошибка запуска останавливает все приложение
+func (a *App) Serve(server *http.Server) {
+	a.servers = append(a.servers, server)
+	base := server.BaseContext
+	server.BaseContext = func(l net.Listener) context.Context {
+		ctx := context.Background()
+		if base != nil {
+			ctx = base(l)
+		}
+		return context.WithValue(ctx, stoppingKey{}, a.stopping)
+	}
+	server.RegisterOnShutdown(func() {
+		a.stopOnce.Do(func() { close(a.stopping) })
+	})
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			select {
+			case a.errs <- err:
+			default:
+			}
+		}
+	}()
+}
+
+// OnClose регистрирует ресурс, который закрывается после остановки серверов и задач
+func (a *App) OnClose(name string, fn func() error) {
+	a.closers = append(a.closers, closer{name: name, fn: fn})
+}
+
+// Run ждет сигнала остановки или ошибки сервера и останавливает приложение.
+// Возвращает ошибку сервера, если остановка вызвана ею.
+func (a *App) Run() error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	a.ready.Store(true)
+	var cause error
+	select {
+	case sig := <-signals:
+		a.logger.Printf("Received %s, shutting down", sig)
+	case cause = <-a.errs:
+		a.logger.Printf("Server failed: %v", cause)
+	}
+
+	go func() {
+		sig := <-signals
+		a.logger.Printf("Received %s again, exiting immediately", sig)
+		os.Exit(1)
+	}()
+
+	a.shutdown(cause == nil)
+	return cause
+}
+
+func (a *App) shutdown(drain bool) {
+	a.ready.Store(false)
+	if drain && a.DrainDelay > 0 {
+		time.Sleep(a.DrainDelay)
+	}
+
+	drained := a.drainServers()
+	stopped := a.stopWorkers()
+	if !drained || !stopped {
+		a.logger.Println("Shutdown incomplete, resources are left open")
+		return
+	}
+
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i].fn(); err != nil {
+			a.logger.Printf("Failed to close %s: %v", a.closers[i].name, err)
+		}
+	}
+	a.logger.Println("Shutdown complete")
+}
+
+// drainServers останавливает серверы; false, если какой-то не дождался запросов
+func (a *App) drainServers() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), a.ShutdownTimeout)
+	defer cancel()
+
+	var drained atomic.Bool
+	drained.Store(true)
+	var wg sync.WaitGroup
+	for _, server := range a.servers {
+		wg.Add(1)
+		go func(server *http.Server) {
+			defer wg.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				a.logger.Printf("HTTP server %s did not drain: %v", server.Addr, err)
+				server.Close()
+				drained.Store(false)
+			}
+		}(server)
+	}
+	wg.Wait()
+	return drained.Load()
+}
+
+// stopWorkers отменяет фоновые задачи и ждет их со своим сроком,
+// который не зависит от того, сколько длилась остановка серверов
+func (a *App) stopWorkers() bool {
+	a.cancel()
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(a.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		a.logger.Printf("Background workers did not stop in %s", a.ShutdownTimeout)
+		return false
+	}
+}
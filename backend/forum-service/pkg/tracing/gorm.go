@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GORM - плагин, записывающий запросы gorm спанами. Как и в auth-service,
+// спан создается только внутри трассы: контекст передается через db.WithContext(ctx).
+func GORM() gorm.Plugin {
+	return gormPlugin{tracer: otel.Tracer("gorm")}
+}
+
+type gormPlugin struct {
+	tracer trace.Tracer
+}
+
+func (gormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("tracing:before_create", p.before("create")),
+		cb.Create().After("*").Register("tracing:after_create", p.after),
+		cb.Query().Before("*").Register("tracing:before_query", p.before("query")),
+		cb.Query().After("*").Register("tracing:after_query", p.after),
+		cb.Update().Before("*").Register("tracing:before_update", p.before("update")),
+		cb.Update().After("*").Register("tracing:after_update", p.after),
+		cb.Delete().Before("*").Register("tracing:before_delete", p.before("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", p.after),
+		cb.Row().Before("*").Register("tracing:before_row", p.before("row")),
+		cb.Row().After("*").Register("tracing:after_row", p.after),
+		cb.Raw().Before("*").Register("tracing:before_raw", p.before("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", p.after),
+	)
+}
+
+func (p gormPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !HasParent(db.Statement.Context) {
+			return
+		}
+		// Контекст запроса не подменяется: его может разделять несколько запросов
+		_, span := p.tracer.Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation)),
+		)
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func (p gormPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	// Текст запроса без значений параметров: в них бывают личные данные
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}